@@ -16,6 +16,67 @@ type Config struct {
 	StartTime int
 	EndTime   int
 	ChannelID int64
+
+	BookingHorizonDays int      // На сколько дней вперёд можно записаться
+	ClosedWeekdays     []int    // Выходные дни недели (0 - воскресенье, 6 - суббота)
+	ClosedDates        []string // Нерабочие даты в формате 02.01.2006
+
+	MinBookingNoticeMinutes int // Минимальное время до начала мойки при записи
+	ChangeCutoffMinutes     int // За сколько минут до начала запрещены отмена и перенос
+
+	WaitlistClaimMinutes int // Сколько минут освободившееся время удерживается для ожидающего
+
+	RecurringMaxOccurrences int // Максимум записей в одной регулярной серии
+
+	// Лимиты на пользователя (0 - без ограничения). Админы не ограничены
+	MaxActiveBookings   int // Активных записей на пользователя
+	MaxBookingsPerDay   int // Записей пользователя на один день
+	MaxBookingsPerPlate int // Активных записей на один номер машины
+	CancelLimit         int // Сколько отмен за CANCEL_WINDOW_HOURS включают паузу
+	CancelWindowHours   int
+	CancelCooldownHours int // Длительность паузы после частых отмен
+
+	PricingFile string // JSON-файл с услугами, классами авто и правилами цен
+
+	SlotStepMinutes int   // Шаг между возможными временами начала мойки
+	BufferMinutes   int   // Время на уборку поста после каждой мойки
+	Bays            int   // Количество постов мойки
+	BayBuffers      []int // Время уборки для каждого поста, если отличается от BUFFER_MINUTES
+
+	NearestSlotsCount int // Сколько ближайших свободных времён предлагать для быстрой записи
+
+	NoShowLimit       int    // После скольких неявок применяется мера, 0 - не применять
+	NoShowPenalty     string // confirm, restrict или block
+	NoShowHorizonDays int    // Горизонт записи при мере restrict
+
+	LocationID      string // Короткий идентификатор мойки в адресе календаря
+	LocationName    string
+	LocationAddress string
+	CalendarAddr    string // Адрес HTTP-сервера календаря, например ":8080". Пусто - сервер не запускается
+	CalendarToken   string // Токен доступа к календарю записей
+	CalendarBaseURL string // Внешний адрес сервера календаря для ссылки на подписку
+
+	WalkInQueue bool // Живая очередь для клиентов без записи
+
+	FlowTimeoutMinutes int // Через сколько минут бездействия сбрасывается начатая запись
+
+	PhoneRequirement string // Запрос телефона при записи: off, optional или required
+
+	ReminderMinutes []int // За сколько минут до мойки отправлять напоминания
+
+	FeedbackDelayMinutes int // Через сколько минут после мойки просить оценку, отрицательное - не просить
+	FeedbackAlertRating  int // Оценки не выше этой сразу отправляются администраторам
+
+	LoyaltyRewardPoints int // Сколько баллов стоит бесплатная мойка, 0 - программа лояльности выключена
+
+	PaymentProviderToken  string // Токен платёжного провайдера из @BotFather, пусто - предоплата выключена
+	PaymentCurrency       string // Валюта счёта, ISO 4217 с двумя знаками после запятой
+	PrepaymentMode        string // Для каких записей нужна предоплата: off, peak (часы с наценкой) или all
+	PaymentTimeoutMinutes int    // Сколько время держится за клиентом до оплаты
+
+	// Адрес Bot API в формате tgbotapi.APIEndpoint ("http://localhost:8081/bot%s/%s"),
+	// например локальная заглушка для проверки оплаты. Пусто - api.telegram.org
+	TelegramAPIEndpoint string
 }
 
 // Инициализируем при первом вызове
@@ -34,6 +95,65 @@ func Load() *Config {
 		StartTime: getEnvAsInt("START_TIME", 8),
 		EndTime:   getEnvAsInt("END_TIME", 20),
 		ChannelID: getEnvAsInt64("CHANNEL_ID", 0),
+
+		BookingHorizonDays: getEnvAsInt("BOOKING_HORIZON_DAYS", 30),
+		ClosedWeekdays:     getEnvAsIntSlice("CLOSED_WEEKDAYS", nil),
+		ClosedDates:        getEnvAsStringSlice("CLOSED_DATES", nil),
+
+		MinBookingNoticeMinutes: getEnvAsInt("MIN_BOOKING_NOTICE_MINUTES", 60),
+		ChangeCutoffMinutes:     getEnvAsInt("CHANGE_CUTOFF_MINUTES", 120),
+
+		WaitlistClaimMinutes: getEnvAsInt("WAITLIST_CLAIM_MINUTES", 15),
+
+		RecurringMaxOccurrences: getEnvAsInt("RECURRING_MAX_OCCURRENCES", 26),
+
+		MaxActiveBookings:   getEnvAsInt("MAX_ACTIVE_BOOKINGS", 3),
+		MaxBookingsPerDay:   getEnvAsInt("MAX_BOOKINGS_PER_DAY", 1),
+		MaxBookingsPerPlate: getEnvAsInt("MAX_BOOKINGS_PER_PLATE", 2),
+		CancelLimit:         getEnvAsInt("CANCEL_LIMIT", 3),
+		CancelWindowHours:   getEnvAsInt("CANCEL_WINDOW_HOURS", 168),
+		CancelCooldownHours: getEnvAsInt("CANCEL_COOLDOWN_HOURS", 24),
+
+		PricingFile: getEnv("PRICING_FILE", "pricing.json"),
+
+		SlotStepMinutes: getEnvAsInt("SLOT_STEP_MINUTES", 60),
+		BufferMinutes:   getEnvAsInt("BUFFER_MINUTES", 0),
+		Bays:            getEnvAsInt("BAYS", 1),
+		BayBuffers:      getEnvAsIntSlice("BAY_BUFFER_MINUTES", nil),
+
+		NearestSlotsCount: getEnvAsInt("NEAREST_SLOTS_COUNT", 5),
+
+		NoShowLimit:       getEnvAsInt("NO_SHOW_LIMIT", 2),
+		NoShowPenalty:     getEnv("NO_SHOW_PENALTY", "confirm"),
+		NoShowHorizonDays: getEnvAsInt("NO_SHOW_HORIZON_DAYS", 3),
+
+		LocationID:      getEnv("LOCATION_ID", "main"),
+		LocationName:    getEnv("LOCATION_NAME", "Автомойка"),
+		LocationAddress: getEnv("LOCATION_ADDRESS", ""),
+		CalendarAddr:    getEnv("CALENDAR_ADDR", ""),
+		CalendarToken:   getEnv("CALENDAR_TOKEN", ""),
+		CalendarBaseURL: getEnv("CALENDAR_BASE_URL", ""),
+
+		WalkInQueue: getEnvAsBool("WALK_IN_QUEUE", false),
+
+		FlowTimeoutMinutes: getEnvAsInt("FLOW_TIMEOUT_MINUTES", 15),
+
+		PhoneRequirement: getEnv("PHONE_REQUIREMENT", "off"),
+
+		ReminderMinutes: getEnvAsIntSlice("REMINDER_MINUTES", []int{24 * 60, 60}),
+
+		FeedbackDelayMinutes: getEnvAsInt("FEEDBACK_DELAY_MINUTES", 60),
+		FeedbackAlertRating:  getEnvAsInt("FEEDBACK_ALERT_RATING", 3),
+
+		// Балл начисляется за каждую оплаченную мойку, поэтому 9 баллов - это "каждая 10-я мойка бесплатно"
+		LoyaltyRewardPoints: getEnvAsInt("LOYALTY_REWARD_POINTS", 9),
+
+		PaymentProviderToken:  getEnv("PAYMENT_PROVIDER_TOKEN", ""),
+		PaymentCurrency:       getEnv("PAYMENT_CURRENCY", "RUB"),
+		PrepaymentMode:        getEnv("PREPAYMENT_MODE", "off"),
+		PaymentTimeoutMinutes: getEnvAsInt("PAYMENT_TIMEOUT_MINUTES", 15),
+
+		TelegramAPIEndpoint: getEnv("TELEGRAM_API_ENDPOINT", ""),
 	}
 }
 
@@ -61,6 +181,15 @@ func getEnvAsInt(key string, defaultValue int) int {
 	}
 	return defaultValue
 }
+func getEnvAsBool(key string, defaultValue bool) bool {
+	if value, exists := os.LookupEnv(key); exists {
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	}
+	return defaultValue
+}
+
 func getEnvAsInt64Slice(key string, defaultValue []int64) []int64 {
 	if value, exists := os.LookupEnv(key); exists {
 		parts := strings.Split(value, ",")
@@ -76,6 +205,33 @@ func getEnvAsInt64Slice(key string, defaultValue []int64) []int64 {
 	return defaultValue
 }
 
+func getEnvAsIntSlice(key string, defaultValue []int) []int {
+	if value, exists := os.LookupEnv(key); exists {
+		var result []int
+		for _, part := range strings.Split(value, ",") {
+			num, err := strconv.Atoi(strings.TrimSpace(part))
+			if err == nil {
+				result = append(result, num)
+			}
+		}
+		return result
+	}
+	return defaultValue
+}
+
+func getEnvAsStringSlice(key string, defaultValue []string) []string {
+	if value, exists := os.LookupEnv(key); exists {
+		var result []string
+		for _, part := range strings.Split(value, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				result = append(result, part)
+			}
+		}
+		return result
+	}
+	return defaultValue
+}
+
 func parseAdminIDs(idsStr string) []int64 {
 	if idsStr == "" {
 		return nil
@@ -5,25 +5,38 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"strconv"
 	"sync"
+	"time"
 
 	"carwash-bot/config"
+	"carwash-bot/internal/fsm"
 	"carwash-bot/internal/models"
+	"carwash-bot/internal/services"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
 type CarWashBot struct {
 	botAPI        *tgbotapi.BotAPI
 	storage       *storage.SQLiteStorage // Меняем тип на SQLiteStorage
-	userStates    map[int64]models.UserState
+	flow          *fsm.Machine[models.UserState]
 	adminID       int64
 	lastMessageID map[int64]int
 	msgIDLock     sync.Mutex
 	config        *config.Config
+	policy        services.BookingPolicy
+	policyLock    sync.RWMutex
+	pricing       *services.PriceList
+	languages     map[int64]userLanguage
+	langLock      sync.RWMutex
 }
 
 func New(config *config.Config) (*CarWashBot, error) {
-	botAPI, err := tgbotapi.NewBotAPI(config.BotToken)
+	endpoint := tgbotapi.APIEndpoint
+	if config.TelegramAPIEndpoint != "" {
+		endpoint = config.TelegramAPIEndpoint
+	}
+	botAPI, err := tgbotapi.NewBotAPIWithAPIEndpoint(config.BotToken, endpoint)
 	if err != nil {
 		return nil, err
 	}
@@ -40,28 +53,68 @@ func New(config *config.Config) (*CarWashBot, error) {
 		return nil, err
 	}
 
-	return &CarWashBot{
+	pricing, err := services.LoadPriceList(config.PricingFile)
+	if err != nil {
+		return nil, err
+	}
+
+	carWashBot := &CarWashBot{
 		botAPI:        botAPI,
 		storage:       storageService,
-		userStates:    make(map[int64]models.UserState),
+		flow:          fsm.New[models.UserState](),
 		adminID:       config.AdminID,
 		lastMessageID: make(map[int64]int),
 		config:        config,
-	}, nil
+		pricing:       pricing,
+		languages:     make(map[int64]userLanguage),
+	}
+	carWashBot.loadPolicy()
+	carWashBot.defineFlow()
+
+	return carWashBot, nil
 }
 func (b *CarWashBot) Start() {
 	log.Printf("Бот запущен: @%s", b.botAPI.Self.UserName)
 	log.Printf("Admin IDs: %v", b.config.AdminIDs) // Правильное логирование
 
+	go b.runWaitlistExpirer()
+	go b.runJobScheduler()
+	if b.config.CalendarAddr != "" {
+		go b.runCalendarFeed()
+	}
+	if b.config.WalkInQueue {
+		go b.runWalkInUpdater()
+	}
+
 	u := tgbotapi.NewUpdate(0)
 	u.Timeout = 60
 	updates := b.botAPI.GetUpdatesChan(u)
 
-	for update := range updates {
-		if update.Message != nil {
-			b.handleMessage(update.Message)
-		} else if update.CallbackQuery != nil {
-			b.handleCallbackQuery(update.CallbackQuery)
+	// Сценарии истекают в том же цикле, что и обработка сообщений, поэтому сценарий
+	// не сбросится посреди обработки ответа пользователя. Это не единственная горутина:
+	// runWaitlistExpirer и runJobScheduler тоже снимают записи и предлагают время листу ожидания,
+	// поэтому обработчики перечитывают запись из базы перед изменением, а сценарии
+	// и сохранённые ID сообщений защищены своими мьютексами
+	flowTicker := time.NewTicker(30 * time.Second)
+	defer flowTicker.Stop()
+
+	for {
+		select {
+		case update, ok := <-updates:
+			if !ok {
+				return
+			}
+			if update.Message != nil {
+				b.handleMessage(update.Message)
+			} else if update.CallbackQuery != nil {
+				b.handleCallbackQuery(update.CallbackQuery)
+			} else if update.PreCheckoutQuery != nil {
+				b.handlePreCheckoutQuery(update.PreCheckoutQuery)
+			} else if update.InlineQuery != nil {
+				b.handleInlineQuery(update.InlineQuery)
+			}
+		case <-flowTicker.C:
+			b.expireFlows()
 		}
 	}
 }
@@ -75,27 +128,102 @@ func (b *CarWashBot) notifyChannel(booking models.Booking) error {
 	msgText := fmt.Sprintf(`🆕 Новая запись на мойку:
 📅 <b>%s</b> в <code>%s</code>
 🚗 <i>%s %s</i>
-👤 ID: %d`,
+🧽 %s — %s
+👤 ID: %d
+🆔 <code>%s</code>`,
 		booking.Date,
 		booking.Time,
 		booking.CarModel,
 		booking.CarNumber,
-		booking.UserID)
+		b.describeService(booking),
+		b.pricing.FormatPrice(booking.Price),
+		booking.UserID,
+		booking.ID)
+
+	if phone := b.customerPhone(booking.UserID); phone != "" {
+		msgText += "\n📞 " + phone
+	}
+	if booking.LoyaltyPoints > 0 {
+		msgText += fmt.Sprintf("\n🎁 Бесплатно, списано баллов: %d", booking.LoyaltyPoints)
+	}
+	if booking.PromoCode != "" {
+		msgText += fmt.Sprintf("\n🏷 Промокод %s: скидка %s", booking.PromoCode, b.pricing.FormatPrice(booking.Discount))
+	}
+	if booking.WasherID != 0 {
+		if staff, err := b.storage.GetStaff(booking.WasherID); err == nil && staff != nil {
+			msgText += fmt.Sprintf("\n👷 Мойщик: %s, пост %d", staff.Name, booking.Bay)
+		}
+	}
 
 	msg := tgbotapi.NewMessage(b.config.ChannelID, msgText)
 	msg.ParseMode = "HTML"
 
-	// Добавляем кнопку отмены для админов
-	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+	// Добавляем кнопки управления для админов
+	msg.ReplyMarkup = b.channelBookingButtons(booking)
+
+	_, err := b.botAPI.Send(msg)
+	return err
+}
+
+// channelBookingButtons - кнопки админа под постом о записи в канале
+func (b *CarWashBot) channelBookingButtons(booking models.Booking) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.NewInlineKeyboardButtonData(
 				"❌ Отменить",
 				fmt.Sprintf("admin_cancel:%s", booking.ID)),
+			tgbotapi.NewInlineKeyboardButtonData(
+				"🔁 Перенести",
+				fmt.Sprintf("admin_resched:%s", booking.ID)),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(
+				"🚫 Не приехал",
+				fmt.Sprintf("admin_noshow:%s", booking.ID)),
+			tgbotapi.NewInlineKeyboardButtonData(
+				"✅ Приехал",
+				fmt.Sprintf("admin_done:%s", booking.ID)),
 		),
 	)
+}
 
-	_, err := b.botAPI.Send(msg)
-	return err
+// notifyAdmins отправляет сообщение всем администраторам
+func (b *CarWashBot) notifyAdmins(text string) {
+	sent := make(map[int64]bool)
+	for _, adminID := range append([]int64{b.adminID}, b.config.AdminIDs...) {
+		if adminID == 0 || sent[adminID] {
+			continue
+		}
+		sent[adminID] = true
+		b.sendMessage(adminID, text)
+	}
+}
+
+// newBookingID генерирует ID записи. ID никогда не используется повторно: к нему по ID
+// привязаны задачи и платежи, и запись на то же время после снятия прежней
+// не должна получить чужую оплату или потерять задачу. Поэтому суффикс добавляется всегда
+func (b *CarWashBot) newBookingID(userID int64, date, timeStr string) string {
+	for {
+		id := fmt.Sprintf("%d-%s-%s-%s", userID, date, timeStr, strconv.FormatInt(time.Now().UnixNano(), 36))
+		if existing, err := b.storage.GetBookingByID(id); err != nil || existing == nil {
+			return id
+		}
+	}
+}
+
+// logBookingEvent сохраняет событие в истории записи
+func (b *CarWashBot) logBookingEvent(booking models.Booking, action, details string, actorID int64) {
+	err := b.storage.AddBookingEvent(models.BookingEvent{
+		BookingID: booking.ID,
+		UserID:    booking.UserID,
+		Action:    action,
+		Details:   details,
+		ActorID:   actorID,
+		Created:   time.Now(),
+	})
+	if err != nil {
+		log.Printf("Ошибка записи истории: %v", err)
+	}
 }
 
 func (b *CarWashBot) answerCallback(callbackID string, text string, showAlert bool) {
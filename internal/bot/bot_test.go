@@ -0,0 +1,114 @@
+package bot
+
+import (
+	"carwash-bot/config"
+	"carwash-bot/internal/fsm"
+	"carwash-bot/internal/models"
+	"carwash-bot/internal/services"
+	"carwash-bot/storage"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// fakeBotAPI - локальная замена Bot API: запоминает вызовы и отвечает успехом
+type fakeBotAPI struct {
+	mu     sync.Mutex
+	calls  map[string][]url.Values
+	nextID int
+}
+
+func (f *fakeBotAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseMultipartForm(1 << 20); err != nil {
+		r.ParseForm()
+	}
+	method := path.Base(r.URL.Path)
+
+	f.mu.Lock()
+	f.calls[method] = append(f.calls[method], r.Form)
+	f.nextID++
+	messageID := f.nextID
+	f.mu.Unlock()
+
+	var result any = true
+	switch method {
+	case "getMe":
+		result = map[string]any{"id": 1, "is_bot": true, "first_name": "Test", "username": "test_bot"}
+	case "sendMessage", "sendInvoice", "sendDocument":
+		chatID, _ := strconv.ParseInt(r.Form.Get("chat_id"), 10, 64)
+		result = map[string]any{"message_id": messageID, "date": time.Now().Unix(), "chat": map[string]any{"id": chatID}}
+	}
+	json.NewEncoder(w).Encode(map[string]any{"ok": true, "result": result})
+}
+
+func (f *fakeBotAPI) requests(method string) []url.Values {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls[method]
+}
+
+// newTestBot собирает бота с чистой базой во временном каталоге и Bot API на fakeBotAPI
+func newTestBot(t *testing.T, cfg *config.Config) (*CarWashBot, *fakeBotAPI) {
+	t.Helper()
+
+	fake := &fakeBotAPI{calls: make(map[string][]url.Values)}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	botAPI, err := tgbotapi.NewBotAPIWithAPIEndpoint("test-token", server.URL+"/bot%s/%s")
+	if err != nil {
+		t.Fatalf("NewBotAPIWithAPIEndpoint: %v", err)
+	}
+
+	store, err := storage.NewSQLiteStorage(filepath.Join(t.TempDir(), "bookings.db"), cfg.StartTime, cfg.EndTime)
+	if err != nil {
+		t.Fatalf("NewSQLiteStorage: %v", err)
+	}
+
+	b := &CarWashBot{
+		botAPI:        botAPI,
+		storage:       store,
+		flow:          fsm.New[models.UserState](),
+		adminID:       cfg.AdminID,
+		lastMessageID: make(map[int64]int),
+		config:        cfg,
+		pricing:       services.DefaultPriceList(),
+		languages:     make(map[int64]userLanguage),
+	}
+	b.loadPolicy()
+	b.defineFlow()
+	return b, fake
+}
+
+// addTestBooking сохраняет запись клиента userID со статусом status
+func addTestBooking(t *testing.T, b *CarWashBot, userID int64, date, timeStr, status string) models.Booking {
+	t.Helper()
+	booking := models.Booking{
+		ID:        b.newBookingID(userID, date, timeStr),
+		Date:      date,
+		Time:      timeStr,
+		CarModel:  "Kia Rio",
+		CarNumber: "А123ВС77",
+		UserID:    userID,
+		Created:   time.Now(),
+		Service:   b.pricing.DefaultService().Code,
+		CarClass:  b.pricing.DefaultCarClass().Code,
+		Price:     1500,
+		Duration:  services.DefaultServiceDuration,
+		Bay:       1,
+		Status:    status,
+	}
+	if err := b.storage.AddBooking(booking); err != nil {
+		t.Fatalf("AddBooking: %v", err)
+	}
+	return booking
+}
@@ -0,0 +1,143 @@
+package bot
+
+import (
+	"carwash-bot/internal/i18n"
+	"fmt"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func (b *CarWashBot) showDaySelection(chatID int64) {
+	b.showCalendar(chatID, 0, time.Now())
+}
+
+// showCalendar отправляет календарь на месяц или редактирует уже отправленный,
+// если передан messageID. Свободные дни считаются для выбранной пользователем услуги
+func (b *CarWashBot) showCalendar(chatID int64, messageID int, month time.Time) {
+	lang := b.lang(chatID)
+	markup := b.buildCalendar(lang, month, b.flow.Data(chatID).SelectedService)
+
+	msg := tgbotapi.NewMessage(chatID, i18n.T(lang, "calendar.header"))
+	msg.ReplyMarkup = markup
+	b.showScreen(chatID, messageID, msg)
+}
+
+// handleCalendarCallback обрабатывает навигацию по месяцам и нажатия на недоступные дни
+func (b *CarWashBot) handleCalendarCallback(query *tgbotapi.CallbackQuery) {
+	data := query.Data
+	userID := query.From.ID
+
+	if strings.HasPrefix(data, "cal_ignore") {
+		reason := strings.TrimPrefix(data, "cal_ignore")
+		switch reason {
+		case ":closed":
+			b.answerCallback(query.ID, b.t(userID, "calendar.closed"), false)
+		case ":full":
+			b.answerCallback(query.ID, b.t(userID, "calendar.full"), false)
+		case ":range":
+			days := b.bookingPolicy().MaxAdvanceDays
+			b.answerCallback(query.ID, b.tn(userID, "calendar.range", days, days), false)
+		default:
+			b.answerCallback(query.ID, "", false)
+		}
+		return
+	}
+
+	month, err := time.Parse("2006-01", strings.TrimPrefix(data, "cal_"))
+	if err != nil {
+		b.answerCallback(query.ID, b.t(userID, "calendar.bad_month"), false)
+		return
+	}
+	b.answerCallback(query.ID, "", false)
+	b.showCalendar(query.Message.Chat.ID, query.Message.MessageID, month)
+}
+
+func (b *CarWashBot) buildCalendar(lang i18n.Lang, month time.Time, service string) tgbotapi.InlineKeyboardMarkup {
+	now := time.Now()
+	first := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, time.UTC)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	lastDay := today.AddDate(0, 0, b.bookingPolicy().MaxAdvanceDays-1)
+
+	var rows [][]tgbotapi.InlineKeyboardButton
+
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(
+			fmt.Sprintf("%s %d", i18n.Month(lang, first.Month()), first.Year()), "cal_ignore"),
+	))
+
+	var weekHeader []tgbotapi.InlineKeyboardButton
+	for _, name := range i18n.WeekdaysShort(lang) {
+		weekHeader = append(weekHeader, tgbotapi.NewInlineKeyboardButtonData(name, "cal_ignore"))
+	}
+	rows = append(rows, weekHeader)
+
+	// Сдвиг первого числа относительно понедельника
+	offset := (int(first.Weekday()) + 6) % 7
+	var week []tgbotapi.InlineKeyboardButton
+	for i := 0; i < offset; i++ {
+		week = append(week, tgbotapi.NewInlineKeyboardButtonData(" ", "cal_ignore"))
+	}
+
+	for day := first; day.Month() == first.Month(); day = day.AddDate(0, 0, 1) {
+		week = append(week, b.calendarDayButton(day, service))
+		if len(week) == 7 {
+			rows = append(rows, week)
+			week = nil
+		}
+	}
+	if len(week) > 0 {
+		for len(week) < 7 {
+			week = append(week, tgbotapi.NewInlineKeyboardButtonData(" ", "cal_ignore"))
+		}
+		rows = append(rows, week)
+	}
+
+	// Навигация показывается только если в соседнем месяце есть доступные даты
+	var nav []tgbotapi.InlineKeyboardButton
+	prevMonth := first.AddDate(0, -1, 0)
+	nextMonth := first.AddDate(0, 1, 0)
+	if first.After(today) {
+		nav = append(nav, tgbotapi.NewInlineKeyboardButtonData(
+			"◀️ "+i18n.Month(lang, prevMonth.Month()), "cal_"+prevMonth.Format("2006-01")))
+	}
+	if !nextMonth.After(lastDay) {
+		nav = append(nav, tgbotapi.NewInlineKeyboardButtonData(
+			i18n.Month(lang, nextMonth.Month())+" ▶️", "cal_"+nextMonth.Format("2006-01")))
+	}
+	if len(nav) > 0 {
+		rows = append(rows, nav)
+	}
+
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(i18n.T(lang, "menu.main"), "main_menu"),
+	))
+
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+func (b *CarWashBot) calendarDayButton(day time.Time, service string) tgbotapi.InlineKeyboardButton {
+	label := fmt.Sprintf("%d", day.Day())
+
+	switch {
+	case !b.isWithinHorizon(day):
+		return tgbotapi.NewInlineKeyboardButtonData(strikethrough(label), "cal_ignore:range")
+	case b.isDayClosed(day):
+		return tgbotapi.NewInlineKeyboardButtonData(strikethrough(label), "cal_ignore:closed")
+	case b.freeSlotsCount(day.Format("02.01.2006"), service) == 0:
+		return tgbotapi.NewInlineKeyboardButtonData(strikethrough(label), "cal_ignore:full")
+	}
+
+	return tgbotapi.NewInlineKeyboardButtonData(label, "day_"+day.Format("02.01.2006"))
+}
+
+// strikethrough зачёркивает текст кнопки, чтобы показать недоступный день
+func strikethrough(text string) string {
+	var sb strings.Builder
+	for _, r := range text {
+		sb.WriteRune(r)
+		sb.WriteRune('̶')
+	}
+	return sb.String()
+}
@@ -0,0 +1,127 @@
+package bot
+
+import (
+	"carwash-bot/internal/models"
+	"log"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// showBookingSummary показывает итог записи перед сохранением.
+// Запись создаётся только после нажатия "Подтвердить"
+func (b *CarWashBot) showBookingSummary(chatID, userID int64, messageID int) {
+	state := b.flow.Data(userID)
+
+	price, err := b.slotPrice(state.SelectedDate, state.SelectedTime, state.SelectedService, state.SelectedClass)
+	if err != nil {
+		log.Printf("Ошибка расчёта цены: %v", err)
+	}
+	service := b.describeService(models.Booking{Service: state.SelectedService, CarClass: state.SelectedClass})
+
+	// Промокод из ссылки подставляется один раз: если клиент его убрал, он не вернётся
+	if !state.PromoOffered {
+		state.PromoOffered = true
+		if state.PromoCode == "" && !state.UseReward {
+			state.PromoCode = b.pendingPromo(userID)
+		}
+	}
+	// Промокод мог перестать подходить после смены времени
+	var promoNote string
+	discount, err := b.promoDiscount(userID, state, price)
+	if err != nil {
+		promoNote = "\n\n" + b.promoError(userID, err)
+		state.PromoCode = ""
+	}
+	b.flow.SetData(userID, state)
+
+	priceText := b.pricing.FormatPrice(price)
+	switch {
+	case state.UseReward:
+		priceText = b.t(userID, "summary.reward_price", b.pricing.FormatPrice(0), b.config.LoyaltyRewardPoints)
+	case state.PromoCode != "":
+		priceText = b.t(userID, "summary.promo_price",
+			b.pricing.FormatPrice(price), b.pricing.FormatPrice(price-discount), state.PromoCode)
+	}
+	text := b.t(userID, "summary.text",
+		state.SelectedDate, state.SelectedTime, state.CarModel, state.CarNumber,
+		service, priceText) + promoNote
+
+	rows := [][]tgbotapi.InlineKeyboardButton{
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(b.t(userID, "summary.confirm"), "book_confirm"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(b.t(userID, "summary.edit_time"), "book_edit_time"),
+			tgbotapi.NewInlineKeyboardButtonData(b.t(userID, "summary.edit_car"), "book_edit_car"),
+		),
+	}
+	switch {
+	case state.UseReward:
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(b.t(userID, "summary.keep_points"), "book_reward"),
+		))
+	case b.canRedeemReward(userID):
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(
+				b.t(userID, "summary.use_points", b.config.LoyaltyRewardPoints), "book_reward"),
+		))
+	}
+	if !state.UseReward {
+		if state.PromoCode != "" {
+			rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData(b.t(userID, "summary.remove_promo"), "book_promo_clear"),
+			))
+		} else {
+			rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData(b.t(userID, "summary.enter_promo"), "book_promo"),
+			))
+		}
+	}
+
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(rows...)
+	b.showScreen(chatID, messageID, msg)
+}
+
+// handleBookingSummaryCallback обрабатывает кнопки итога записи:
+// book_confirm, book_edit_time, book_edit_car, book_reward, book_promo, book_promo_clear
+func (b *CarWashBot) handleBookingSummaryCallback(chatID, userID int64, messageID int, data string) {
+	if b.flow.State(userID) != stateConfirming {
+		b.sendMessage(chatID, b.t(userID, "flow.stale"))
+		return
+	}
+
+	switch data {
+	case "book_confirm":
+		b.deleteLastMessage(chatID)
+		b.createBooking(chatID, userID)
+
+	case "book_edit_time":
+		state := b.flow.Data(userID)
+		state.SelectedTime = ""
+		if b.setState(chatID, userID, stateChoosingTime, state) {
+			b.showTimeSlots(chatID, userID, messageID, state.SelectedDate)
+		}
+
+	case "book_edit_car":
+		b.askCarInfo(chatID, userID, messageID)
+
+	case "book_reward":
+		state := b.flow.Data(userID)
+		state.UseReward = !state.UseReward && b.canRedeemReward(userID)
+		if state.UseReward {
+			state.PromoCode = ""
+		}
+		b.flow.SetData(userID, state)
+		b.showBookingSummary(chatID, userID, messageID)
+
+	case "book_promo":
+		b.askPromoCode(chatID, userID, messageID)
+
+	case "book_promo_clear":
+		state := b.flow.Data(userID)
+		state.PromoCode = ""
+		b.flow.SetData(userID, state)
+		b.showBookingSummary(chatID, userID, messageID)
+	}
+}
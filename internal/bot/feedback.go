@@ -0,0 +1,349 @@
+package bot
+
+import (
+	"carwash-bot/internal/fsm"
+	"carwash-bot/internal/i18n"
+	"carwash-bot/internal/models"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Комментарий к оценке обрезается до этой длины
+const maxFeedbackComment = 1000
+
+// feedbackJob - просьба оценить мойку через FEEDBACK_DELAY_MINUTES после её окончания
+func (b *CarWashBot) feedbackJob(booking models.Booking) (models.Job, bool) {
+	delay := b.config.FeedbackDelayMinutes
+	if delay < 0 {
+		return models.Job{}, false
+	}
+	end, err := booking.EndsAt()
+	if err != nil {
+		log.Printf("Ошибка планирования отзыва для %s: %v", booking.ID, err)
+		return models.Job{}, false
+	}
+	return models.Job{
+		Kind:          models.JobFeedback,
+		BookingID:     booking.ID,
+		Slot:          booking.Date + " " + booking.Time,
+		OffsetMinutes: delay,
+		RunAt:         end.Add(time.Duration(delay) * time.Minute),
+	}, true
+}
+
+// scheduleFeedbackRequest планирует просьбу оценить мойку
+func (b *CarWashBot) scheduleFeedbackRequest(booking models.Booking) {
+	job, ok := b.feedbackJob(booking)
+	if !ok || !job.RunAt.After(time.Now()) {
+		return
+	}
+	if err := b.storage.ScheduleJob(job); err != nil {
+		log.Printf("Ошибка планирования отзыва для %s: %v", booking.ID, err)
+	}
+}
+
+// requestFeedbackSoon просит оценку сразу после окончания мойки, не дожидаясь
+// FEEDBACK_DELAY_MINUTES. Вызывается, когда администратор отметил, что клиент приехал
+func (b *CarWashBot) requestFeedbackSoon(booking models.Booking) {
+	job, ok := b.feedbackJob(booking)
+	if !ok {
+		return
+	}
+	runAt := time.Now()
+	if end, err := booking.EndsAt(); err == nil && end.After(runAt) {
+		runAt = end
+	}
+	job.RunAt = runAt
+
+	if err := b.storage.ScheduleJob(job); err != nil {
+		log.Printf("Ошибка планирования отзыва для %s: %v", booking.ID, err)
+		return
+	}
+	if err := b.storage.ExpediteJobs(models.JobFeedback, booking.ID, runAt); err != nil {
+		log.Printf("Ошибка планирования отзыва для %s: %v", booking.ID, err)
+	}
+}
+
+// sendFeedbackRequest просит клиента оценить мойку. Возвращает false, если запись
+// отменена, перенесена, клиент не приехал или оценка уже поставлена
+func (b *CarWashBot) sendFeedbackRequest(job models.Job) bool {
+	booking, err := b.storage.GetBookingByID(job.BookingID)
+	if err != nil {
+		log.Printf("Ошибка получения записи %s: %v", job.BookingID, err)
+		return false
+	}
+	if booking == nil || booking.Date+" "+booking.Time != job.Slot {
+		return false
+	}
+	if booking.Status != models.BookingActive && booking.Status != models.BookingCompleted {
+		return false
+	}
+	if feedback, err := b.storage.GetFeedback(booking.ID); err != nil || feedback != nil {
+		return false
+	}
+
+	userID := booking.UserID
+	var row []tgbotapi.InlineKeyboardButton
+	for rating := 1; rating <= 5; rating++ {
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(
+			fmt.Sprintf("%d ⭐", rating), fmt.Sprintf("fb:%s:%d", booking.ID, rating)))
+	}
+
+	msg := tgbotapi.NewMessage(userID, b.t(userID, "feedback.ask",
+		booking.Date, booking.Time, booking.CarModel, booking.CarNumber))
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(row)
+	if _, err := b.botAPI.Send(msg); err != nil {
+		log.Printf("Ошибка отправки просьбы об отзыве по записи %s: %v", booking.ID, err)
+	}
+	return true
+}
+
+// handleFeedbackCallback сохраняет оценку: fb:<id записи>:<1-5>.
+// ID записи содержит двоеточие во времени, поэтому оценка берётся после последнего
+func (b *CarWashBot) handleFeedbackCallback(query *tgbotapi.CallbackQuery) {
+	chatID := query.Message.Chat.ID
+	userID := query.From.ID
+
+	data := strings.TrimPrefix(query.Data, "fb:")
+	i := strings.LastIndex(data, ":")
+	if i < 0 {
+		return
+	}
+	bookingID := data[:i]
+	rating, err := strconv.Atoi(data[i+1:])
+	if err != nil || rating < 1 || rating > 5 {
+		return
+	}
+
+	booking, err := b.storage.GetBookingByID(bookingID)
+	if err != nil || booking == nil || booking.UserID != userID {
+		b.sendMessage(chatID, b.t(userID, "cancel.not_found"))
+		return
+	}
+
+	previous, err := b.storage.GetFeedback(bookingID)
+	if err != nil {
+		log.Printf("Ошибка получения отзыва: %v", err)
+	}
+	err = b.storage.SaveFeedbackRating(models.Feedback{
+		BookingID: bookingID,
+		UserID:    userID,
+		WasherID:  booking.WasherID,
+		Rating:    rating,
+	})
+	if err != nil {
+		log.Printf("Ошибка сохранения отзыва: %v", err)
+		b.sendMessage(chatID, b.t(userID, "feedback.error"))
+		return
+	}
+
+	edit := tgbotapi.NewEditMessageText(chatID, query.Message.MessageID,
+		b.t(userID, "feedback.rated", booking.Date, booking.Time, strings.Repeat("⭐", rating)))
+	if _, err := b.botAPI.Send(edit); err != nil {
+		log.Printf("Ошибка обновления сообщения: %v", err)
+	}
+
+	// Об одной и той же низкой оценке администраторам сообщаем один раз
+	if b.isLowRating(rating) && (previous == nil || !b.isLowRating(previous.Rating)) {
+		b.alertLowRating(*booking, rating, "")
+	}
+
+	// Комментарий спрашиваем, только если клиент не занят другим сценарием
+	if b.flow.State(userID) != fsm.Idle {
+		return
+	}
+	if !b.setState(chatID, userID, stateEnteringFeedback, models.UserState{FeedbackBookingID: bookingID}) {
+		return
+	}
+	msg := tgbotapi.NewMessage(chatID, b.t(userID, "feedback.ask_comment"))
+	msg.ReplyMarkup = tgbotapi.NewReplyKeyboard(
+		tgbotapi.NewKeyboardButtonRow(tgbotapi.NewKeyboardButton(b.t(userID, "feedback.skip"))),
+	)
+	if _, err := b.botAPI.Send(msg); err != nil {
+		log.Printf("Ошибка отправки сообщения: %v", err)
+	}
+}
+
+// handleFeedbackComment сохраняет комментарий к оценке или пропускает его
+func (b *CarWashBot) handleFeedbackComment(chatID, userID int64, text string) {
+	bookingID := b.flow.Data(userID).FeedbackBookingID
+	b.flow.Reset(userID)
+
+	if i18n.Matches(text, "feedback.skip") || strings.TrimSpace(text) == "" {
+		b.finishFeedback(chatID, userID, "feedback.thanks")
+		return
+	}
+
+	comment := strings.TrimSpace(text)
+	if runes := []rune(comment); len(runes) > maxFeedbackComment {
+		comment = string(runes[:maxFeedbackComment])
+	}
+	if err := b.storage.SetFeedbackComment(bookingID, comment); err != nil {
+		log.Printf("Ошибка сохранения комментария: %v", err)
+		b.finishFeedback(chatID, userID, "feedback.error")
+		return
+	}
+	b.finishFeedback(chatID, userID, "feedback.thanks")
+
+	feedback, err := b.storage.GetFeedback(bookingID)
+	if err != nil || feedback == nil || !b.isLowRating(feedback.Rating) {
+		return
+	}
+	if booking, err := b.storage.GetBookingByID(bookingID); err == nil && booking != nil {
+		b.alertLowRating(*booking, feedback.Rating, comment)
+	}
+}
+
+// finishFeedback возвращает главное меню вместо кнопки пропуска
+func (b *CarWashBot) finishFeedback(chatID, userID int64, key string) {
+	reply := tgbotapi.NewMessage(chatID, b.t(userID, key))
+	reply.ReplyMarkup = b.mainMenuKeyboard(userID)
+	if _, err := b.botAPI.Send(reply); err != nil {
+		log.Printf("Ошибка отправки сообщения: %v", err)
+	}
+}
+
+func (b *CarWashBot) isLowRating(rating int) bool {
+	return rating <= b.config.FeedbackAlertRating
+}
+
+// alertLowRating сообщает администраторам о низкой оценке, чтобы они могли сразу связаться с клиентом
+func (b *CarWashBot) alertLowRating(booking models.Booking, rating int, comment string) {
+	text := fmt.Sprintf("⚠️ Низкая оценка: %s (%d из 5)\n📅 %s в %s · пост %d\n🚗 %s %s\n👤 ID: %d",
+		strings.Repeat("⭐", rating), rating, booking.Date, booking.Time, booking.Bay,
+		booking.CarModel, booking.CarNumber, booking.UserID)
+	if phone := b.customerPhone(booking.UserID); phone != "" {
+		text += "\n📞 " + phone
+	}
+	if name := b.washerName(booking.WasherID); name != "" {
+		text += "\n👷 Мойщик: " + name
+	}
+	if comment != "" {
+		text += "\n💬 " + comment
+	}
+	b.notifyAdmins(text)
+}
+
+// washerName возвращает имя мойщика или пустую строку, если мойщик не назначен
+func (b *CarWashBot) washerName(washerID int64) string {
+	if washerID == 0 {
+		return ""
+	}
+	staff, err := b.storage.GetStaff(washerID)
+	if err != nil || staff == nil {
+		return ""
+	}
+	return staff.Name
+}
+
+// handleFeedbackCommand показывает админу сводку оценок: /feedback [дней]
+func (b *CarWashBot) handleFeedbackCommand(chatID, userID int64, text string) {
+	if !b.isAdmin(userID) {
+		b.sendMessage(chatID, "❌ Команда доступна только администратору")
+		return
+	}
+
+	days := 30
+	if args := strings.Fields(text)[1:]; len(args) > 0 {
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n <= 0 {
+			b.sendMessage(chatID, "Использование: /feedback [число дней]")
+			return
+		}
+		days = n
+	}
+
+	feedback, err := b.storage.FeedbackSince(time.Now().AddDate(0, 0, -days))
+	if err != nil {
+		b.sendMessage(chatID, "⚠️ Ошибка при получении оценок")
+		return
+	}
+	b.sendMessage(chatID, b.feedbackReport(feedback, days))
+}
+
+type ratingStats struct {
+	count int
+	sum   int
+}
+
+func (s ratingStats) average() float64 {
+	if s.count == 0 {
+		return 0
+	}
+	return float64(s.sum) / float64(s.count)
+}
+
+// feedbackReport - сводка оценок: средняя, распределение, мойщики и последние низкие оценки
+func (b *CarWashBot) feedbackReport(feedback []models.Feedback, days int) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("📊 Оценки за %d дн.\n\n", days))
+	if len(feedback) == 0 {
+		sb.WriteString("Оценок пока нет")
+		return sb.String()
+	}
+
+	var total ratingStats
+	var byRating [6]int
+	byWasher := make(map[int64]ratingStats)
+	for _, f := range feedback {
+		total.count++
+		total.sum += f.Rating
+		byRating[f.Rating]++
+		stats := byWasher[f.WasherID]
+		stats.count++
+		stats.sum += f.Rating
+		byWasher[f.WasherID] = stats
+	}
+
+	sb.WriteString(fmt.Sprintf("Всего: %d, средняя: %.1f\n", total.count, total.average()))
+	for rating := 5; rating >= 1; rating-- {
+		sb.WriteString(fmt.Sprintf("%d ⭐ — %d\n", rating, byRating[rating]))
+	}
+
+	washerIDs := make([]int64, 0, len(byWasher))
+	for id := range byWasher {
+		washerIDs = append(washerIDs, id)
+	}
+	sort.Slice(washerIDs, func(i, j int) bool {
+		return byWasher[washerIDs[i]].average() > byWasher[washerIDs[j]].average()
+	})
+	sb.WriteString("\n👷 По мойщикам:\n")
+	for _, id := range washerIDs {
+		name := b.washerName(id)
+		if name == "" {
+			name = "Без мойщика"
+		}
+		stats := byWasher[id]
+		sb.WriteString(fmt.Sprintf("%s — %.1f (%d)\n", name, stats.average(), stats.count))
+	}
+
+	// Последние низкие оценки, новые первыми
+	var low []string
+	for _, f := range feedback {
+		if !b.isLowRating(f.Rating) || len(low) == 10 {
+			continue
+		}
+		line := fmt.Sprintf("• %s · %d ⭐", f.Created.Format("02.01 15:04"), f.Rating)
+		if booking, err := b.storage.GetBookingByID(f.BookingID); err == nil && booking != nil {
+			line = fmt.Sprintf("• %s %s · %d ⭐ · %s", booking.Date, booking.Time, f.Rating, booking.CarNumber)
+		}
+		if name := b.washerName(f.WasherID); name != "" {
+			line += " · " + name
+		}
+		if f.Comment != "" {
+			line += "\n  💬 " + f.Comment
+		}
+		low = append(low, line)
+	}
+	if len(low) > 0 {
+		sb.WriteString("\n⚠️ Низкие оценки:\n")
+		sb.WriteString(strings.Join(low, "\n"))
+	}
+	return sb.String()
+}
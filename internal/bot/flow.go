@@ -0,0 +1,123 @@
+package bot
+
+import (
+	"carwash-bot/internal/fsm"
+	"carwash-bot/internal/i18n"
+	"carwash-bot/internal/models"
+	"log"
+	"strings"
+	"time"
+)
+
+// Состояния диалога записи
+const (
+	stateChoosingService    fsm.State = "choosing_service"
+	stateChoosingDay        fsm.State = "choosing_day"
+	stateChoosingTime       fsm.State = "choosing_time"
+	stateChoosingClass      fsm.State = "choosing_class"
+	stateEnteringCarInfo    fsm.State = "entering_car_info"
+	stateEnteringPhone      fsm.State = "entering_phone"
+	stateConfirming         fsm.State = "confirming"
+	stateEnteringPromo      fsm.State = "entering_promo"
+	stateSettingUpSeries    fsm.State = "setting_up_series"
+	stateEnteringRecurUntil fsm.State = "entering_recur_until"
+	stateEnteringFeedback   fsm.State = "entering_feedback"
+)
+
+// Кнопки главного меню работают как команды в любом состоянии
+var menuCommands = []string{
+	"menu.main", "menu.book", "menu.asap", "menu.walkin",
+	"menu.schedule", "menu.my_bookings", "menu.cancel", "menu.balance", "menu.help",
+}
+
+// defineFlow описывает сценарии диалога: состояния, переходы и обработчики текста.
+// В состояниях выбора кнопками обработчика текста нет - текст идёт в обычные команды
+func (b *CarWashBot) defineFlow() {
+	timeout := time.Duration(b.config.FlowTimeoutMinutes) * time.Minute
+
+	b.flow.Define(stateChoosingService, fsm.StateDef{
+		Timeout: timeout,
+		Next:    []fsm.State{stateChoosingService, stateChoosingDay},
+	})
+	b.flow.Define(stateChoosingDay, fsm.StateDef{
+		Timeout: timeout,
+		Next:    []fsm.State{stateChoosingDay, stateChoosingTime},
+	})
+	b.flow.Define(stateChoosingTime, fsm.StateDef{
+		Timeout: timeout,
+		Next:    []fsm.State{stateChoosingTime, stateChoosingDay, stateChoosingClass, stateEnteringCarInfo, stateConfirming},
+	})
+	b.flow.Define(stateChoosingClass, fsm.StateDef{
+		Timeout: timeout,
+		Next:    []fsm.State{stateChoosingClass, stateChoosingDay, stateChoosingTime, stateEnteringCarInfo},
+	})
+	b.flow.Define(stateEnteringCarInfo, fsm.StateDef{
+		Handler: b.handleCarInfoInput,
+		Timeout: timeout,
+		Next:    []fsm.State{stateEnteringCarInfo, stateChoosingDay, stateChoosingTime, stateChoosingClass, stateEnteringPhone, stateConfirming},
+	})
+	b.flow.Define(stateEnteringPhone, fsm.StateDef{
+		Handler: b.handlePhoneInput,
+		Timeout: timeout,
+		Next:    []fsm.State{stateEnteringPhone, stateConfirming},
+	})
+	b.flow.Define(stateConfirming, fsm.StateDef{
+		Timeout: timeout,
+		Next:    []fsm.State{stateConfirming, stateChoosingDay, stateChoosingTime, stateEnteringCarInfo, stateEnteringPromo},
+	})
+	b.flow.Define(stateEnteringPromo, fsm.StateDef{
+		Handler: b.handlePromoInput,
+		Timeout: timeout,
+		Next:    []fsm.State{stateEnteringPromo, stateConfirming},
+	})
+	b.flow.Define(stateSettingUpSeries, fsm.StateDef{
+		Timeout: timeout,
+		Next:    []fsm.State{stateSettingUpSeries, stateEnteringRecurUntil},
+	})
+	b.flow.Define(stateEnteringRecurUntil, fsm.StateDef{
+		Handler: b.handleRecurUntilInput,
+		Timeout: timeout,
+		Next:    []fsm.State{stateEnteringRecurUntil},
+	})
+	b.flow.Define(stateEnteringFeedback, fsm.StateDef{
+		Handler: b.handleFeedbackComment,
+		Timeout: timeout,
+	})
+}
+
+// setState переводит пользователя в состояние. Если переход не разрешён,
+// сценарий сбрасывается и пользователю предлагается начать заново
+func (b *CarWashBot) setState(chatID, userID int64, state fsm.State, data models.UserState) bool {
+	if err := b.flow.Transition(userID, chatID, state, data); err != nil {
+		log.Printf("Пользователь %d: %v", userID, err)
+		b.flow.Reset(userID)
+		b.sendMessage(chatID, b.t(userID, "flow.outdated"))
+		return false
+	}
+	return true
+}
+
+// isGlobalCommand - команды, которые прерывают любой сценарий
+func isGlobalCommand(text string) bool {
+	if strings.HasPrefix(text, "/") {
+		return true
+	}
+	for _, key := range menuCommands {
+		if i18n.Matches(text, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// expireFlows сбрасывает сценарии пользователей, которые долго не отвечали
+func (b *CarWashBot) expireFlows() {
+	for _, expired := range b.flow.Expire() {
+		// Комментарий к оценке необязателен: просто возвращаем меню вместо кнопки пропуска
+		if expired.State == stateEnteringFeedback {
+			b.finishFeedback(expired.ChatID, expired.UserID, "feedback.thanks")
+			continue
+		}
+		b.sendMessage(expired.ChatID, b.t(expired.UserID, "flow.expired"))
+	}
+}
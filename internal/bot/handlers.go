@@ -2,11 +2,16 @@ package bot
 
 import (
 	_ "carwash-bot/config"
+	"carwash-bot/internal/fsm"
+	"carwash-bot/internal/i18n"
 	"carwash-bot/internal/models"
+	"carwash-bot/internal/services"
+	"errors"
 	"fmt"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"log"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -15,34 +20,104 @@ func (b *CarWashBot) handleMessage(msg *tgbotapi.Message) {
 	chatID := msg.Chat.ID
 	userID := msg.From.ID
 	text := msg.Text
+	b.rememberLanguage(msg.From)
 
-	// Проверяем состояние пользователя (ожидание данных авто)
-	if state, exists := b.userStates[userID]; exists {
-		if state.AwaitingCarInfo {
-			b.handleCarInfoInput(chatID, userID, text)
+	// Служебное сообщение об оплате счёта, тоже без текста
+	if msg.SuccessfulPayment != nil {
+		b.handleSuccessfulPayment(msg)
+		return
+	}
+
+	// Контакт приходит без текста, когда клиент делится телефоном
+	if msg.Contact != nil {
+		b.handleContact(msg)
+		return
+	}
+
+	// Команды и кнопки меню прерывают любой начатый сценарий,
+	// остальной текст обрабатывает текущее состояние (например, ввод данных авто)
+	if isGlobalCommand(text) {
+		if text == "/cancel" && b.flow.State(userID) != fsm.Idle {
+			b.flow.Reset(userID)
+			b.sendMessage(chatID, b.t(userID, "flow.cancelled"))
+			b.sendWelcomeMessage(chatID)
 			return
 		}
+		b.flow.Reset(userID)
+	} else if b.flow.Handle(chatID, userID, text) {
+		return
 	}
 
 	// Обрабатываем команды
 	switch {
-	case text == "/start" || text == "/menu" || text == "🏠 Главное меню":
+	case text == "/start" || text == "/menu" || i18n.Matches(text, "menu.main"):
 		b.sendWelcomeMessage(chatID)
 
-	case text == "📝 Записаться" || text == "/book":
-		b.showDaySelection(chatID)
+	case strings.HasPrefix(text, "/start "):
+		b.handleStartPayload(chatID, userID, strings.TrimSpace(strings.TrimPrefix(text, "/start ")))
 
-	case text == "🕒 Расписание" || text == "/schedule":
+	case text == "/book" || i18n.Matches(text, "menu.book"):
+		b.startBooking(chatID, userID)
+
+	case text == "/asap" || i18n.Matches(text, "menu.asap"):
+		b.showNearestSlots(chatID, userID)
+
+	case text == "/walkin" || i18n.Matches(text, "menu.walkin"):
+		b.joinWalkIn(chatID, userID)
+
+	case text == "/walkins":
+		b.handleWalkInsCommand(chatID, userID)
+
+	case text == "/schedule" || i18n.Matches(text, "menu.schedule"):
 		b.showSchedule(chatID)
 
-	case text == "❌ Мои записи" || text == "/mybookings":
+	case text == "/mybookings" || i18n.Matches(text, "menu.my_bookings"):
 		b.showUserBookings(msg.Chat.ID, msg.From.ID)
 
-	case text == "❌ Отменить запись" || text == "/cancel":
+	case text == "/cancel" || i18n.Matches(text, "menu.cancel"):
 		b.handleCancelCommand(chatID, userID)
 
+	case text == "/balance" || i18n.Matches(text, "menu.balance"):
+		b.showBalance(chatID, userID)
+
+	case strings.HasPrefix(text, "/points"):
+		b.handlePointsCommand(chatID, userID, text)
+
+	case strings.HasPrefix(text, "/promo"):
+		b.handlePromoCommand(chatID, userID, text)
+
+	case text == "/language":
+		b.handleLanguageCommand(chatID, userID)
+
+	case strings.HasPrefix(text, "/policy"):
+		b.handlePolicyCommand(chatID, userID, text)
+
+	case strings.HasPrefix(text, "/staff"):
+		b.handleStaffCommand(chatID, userID, text)
+
+	case strings.HasPrefix(text, "/shifts"):
+		b.handleShiftsCommand(chatID, userID, text)
+
+	case strings.HasPrefix(text, "/shift"):
+		b.handleShiftCommand(chatID, userID, text)
+
+	case strings.HasPrefix(text, "/assign"):
+		b.handleAssignCommand(chatID, userID, text)
+
+	case text == "/calendar":
+		b.handleCalendarCommand(chatID, userID)
+
+	case strings.HasPrefix(text, "/feedback"):
+		b.handleFeedbackCommand(chatID, userID, text)
+
+	case strings.HasPrefix(text, "/day"):
+		b.handleDayCommand(chatID, userID, text)
+
+	case strings.HasPrefix(text, "/queue"):
+		b.handleQueueCommand(chatID, userID, text)
+
 	default:
-		b.sendMessage(chatID, "Я не понимаю эту команду. Используйте кнопки меню.")
+		b.sendMessage(chatID, b.t(userID, "unknown_command"))
 	}
 }
 
@@ -50,31 +125,84 @@ func (b *CarWashBot) handleCallbackQuery(query *tgbotapi.CallbackQuery) {
 	chatID := query.Message.Chat.ID
 	userID := query.From.ID
 	data := query.Data
+	messageID := query.Message.MessageID
+	b.rememberLanguage(query.From)
+	b.flow.Touch(userID)
 
-	// Отвечаем на callback (убираем "часы ожидания")
-	callback := tgbotapi.NewCallback(query.ID, "")
-	if _, err := b.botAPI.Request(callback); err != nil {
-		log.Printf("Ошибка ответа на callback: %v", err)
+	// Кнопки старых экранов записи не выполняются, а убираются
+	if isScreenCallback(data) && b.isStaleScreen(chatID, messageID) {
+		b.refreshStaleScreen(query)
+		return
+	}
+
+	// Отвечаем на callback (убираем "часы ожидания").
+	// Для календаря и админских действий ответ с текстом формируется ниже
+	if !strings.HasPrefix(data, "cal_") && !strings.HasPrefix(data, "admin_") {
+		callback := tgbotapi.NewCallback(query.ID, "")
+		if _, err := b.botAPI.Request(callback); err != nil {
+			log.Printf("Ошибка ответа на callback: %v", err)
+		}
 	}
 
 	switch {
+	case strings.HasPrefix(data, "cal_"):
+		b.handleCalendarCallback(query)
+
 	case strings.HasPrefix(data, "day_"):
 		dateStr := strings.TrimPrefix(data, "day_")
-		b.handleDaySelection(chatID, userID, dateStr)
+		b.handleDaySelection(chatID, userID, messageID, dateStr)
 
 	case strings.HasPrefix(data, "time_"):
 		timeStr := strings.TrimPrefix(data, "time_")
-		b.handleTimeSelection(chatID, userID, timeStr)
+		b.handleTimeSelection(chatID, userID, messageID, timeStr)
 
 	case data == "main_menu":
+		b.flow.Reset(userID)
 		b.sendWelcomeMessage(chatID)
 
+	case strings.HasPrefix(data, "asap_"):
+		b.handleNearestSlotSelection(chatID, userID, messageID, strings.TrimPrefix(data, "asap_"))
+
+	case strings.HasPrefix(data, "wq_"):
+		b.handleWalkInCallback(chatID, userID, data)
+
+	case strings.HasPrefix(data, "admin_wq_"):
+		b.handleWalkInAdminCallback(query)
+
+	case strings.HasPrefix(data, "svc_"):
+		b.handleServiceSelection(chatID, userID, messageID, strings.TrimPrefix(data, "svc_"))
+
+	case strings.HasPrefix(data, "cls_"):
+		b.handleCarClassSelection(chatID, userID, messageID, strings.TrimPrefix(data, "cls_"))
+
+	case strings.HasPrefix(data, "book_"):
+		b.handleBookingSummaryCallback(chatID, userID, messageID, data)
+
 	case strings.HasPrefix(data, "cancel_"):
 		bookingID := strings.TrimPrefix(data, "cancel_")
 		b.handleBookingCancellation(chatID, userID, bookingID)
 
+	case strings.HasPrefix(data, "wl_"):
+		b.handleWaitlistCallback(chatID, userID, data)
+
+	case strings.HasPrefix(data, "fb:"):
+		b.handleFeedbackCallback(query)
+	case strings.HasPrefix(data, "rem_ok:"):
+		b.handleReminderCallback(query)
+	case strings.HasPrefix(data, "resched_"):
+		b.startReschedule(chatID, userID, strings.TrimPrefix(data, "resched_"))
+
+	case strings.HasPrefix(data, "lang_"):
+		b.handleLanguageCallback(chatID, userID, data)
+
+	case strings.HasPrefix(data, "rec_"):
+		b.handleRecurringCallback(chatID, userID, messageID, data)
+
+	case strings.HasPrefix(data, "series_cancel_"):
+		b.handleSeriesCancellation(chatID, userID, strings.TrimPrefix(data, "series_cancel_"))
+
 	case data == "back_to_dates":
-		b.showDaySelection(chatID)
+		b.showCalendar(chatID, messageID, time.Now())
 
 	case strings.HasPrefix(data, "admin_cancel:"):
 		if !b.isAdmin(query.From.ID) {
@@ -82,12 +210,21 @@ func (b *CarWashBot) handleCallbackQuery(query *tgbotapi.CallbackQuery) {
 			return
 		}
 		bookingID := strings.TrimPrefix(data, "admin_cancel:")
-		err := b.storage.DeleteBooking(bookingID)
+		booking, err := b.storage.GetBookingByID(bookingID)
+		if err != nil || booking == nil {
+			b.answerCallback(query.ID, "⚠️ Запись не найдена", true)
+			return
+		}
+		err = b.storage.DeleteBooking(bookingID)
 		if err != nil {
 			b.answerCallback(query.ID, "⚠️ Не удалось отменить запись", true)
 			return
 		}
 		b.answerCallback(query.ID, "✅ Запись отменена", false)
+		b.logBookingEvent(*booking, models.EventCancelled, booking.Date+" "+booking.Time, query.From.ID)
+		b.refundLoyaltyPoints(*booking, query.From.ID)
+		b.refundPrepayment(*booking, query.From.ID)
+		b.offerFreedSlot(booking.Date, booking.Time)
 
 		// Обновляем сообщение в канале
 		editMsg := tgbotapi.NewEditMessageText(
@@ -97,6 +234,51 @@ func (b *CarWashBot) handleCallbackQuery(query *tgbotapi.CallbackQuery) {
 		)
 		editMsg.ParseMode = "HTML"
 		b.botAPI.Send(editMsg)
+	case strings.HasPrefix(data, "admin_resched:"):
+		if !b.isAdmin(query.From.ID) {
+			b.answerCallback(query.ID, "❌ Только администратор может переносить записи", true)
+			return
+		}
+		b.answerCallback(query.ID, "🔁 Выберите новое время в личном чате с ботом", false)
+		// Выбор времени продолжается в личном чате админа, а не в канале
+		b.startReschedule(query.From.ID, query.From.ID, strings.TrimPrefix(data, "admin_resched:"))
+
+	case strings.HasPrefix(data, "admin_series_cancel:"):
+		if !b.isAdmin(query.From.ID) {
+			b.answerCallback(query.ID, "❌ Только администратор может отменять записи", true)
+			return
+		}
+		seriesID, err := strconv.ParseInt(strings.TrimPrefix(data, "admin_series_cancel:"), 10, 64)
+		if err != nil {
+			b.answerCallback(query.ID, "⚠️ Ошибка формата данных", true)
+			return
+		}
+		cancelled, _, err := b.cancelSeries(query.From.ID, seriesID)
+		if err != nil {
+			b.answerCallback(query.ID, "⚠️ Не удалось отменить серию", true)
+			return
+		}
+		b.answerCallback(query.ID, fmt.Sprintf("✅ Отменено записей: %d", cancelled), false)
+
+		editMsg := tgbotapi.NewEditMessageText(
+			b.config.ChannelID,
+			query.Message.MessageID,
+			fmt.Sprintf("❌ СЕРИЯ ОТМЕНЕНА АДМИНОМ\n%s", query.Message.Text),
+		)
+		b.botAPI.Send(editMsg)
+
+	case strings.HasPrefix(data, "admin_noshow:"), strings.HasPrefix(data, "admin_done:"),
+		strings.HasPrefix(data, "admin_confirm:"), strings.HasPrefix(data, "admin_reject:"):
+		b.handleAttendanceCallback(query)
+
+	case strings.HasPrefix(data, "admin_dayview:"):
+		if !b.isAdmin(query.From.ID) {
+			b.answerCallback(query.ID, "❌ Действие доступно только администратору", true)
+			return
+		}
+		b.answerCallback(query.ID, "", false)
+		b.showAdminDay(chatID, query.Message.MessageID, strings.TrimPrefix(data, "admin_dayview:"))
+
 	default:
 		b.answerCallback(query.ID, "", false) // Просто убираем "часы ожидания"
 	}
@@ -104,159 +286,284 @@ func (b *CarWashBot) handleCallbackQuery(query *tgbotapi.CallbackQuery) {
 }
 func (b *CarWashBot) sendWelcomeMessage(chatID int64) {
 
-	msgText := `🚗 *Добро пожаловать в бота автомойки!* 🧼
+	msg := tgbotapi.NewMessage(chatID, b.t(chatID, "welcome"))
+	msg.ParseMode = "Markdown"
+	msg.ReplyMarkup = b.mainMenuKeyboard(chatID)
+	b.sendMessageWithSave(chatID, msg)
+}
 
-    
-Выберите действие:`
+// mainMenuKeyboard - постоянная клавиатура главного меню на языке пользователя
+func (b *CarWashBot) mainMenuKeyboard(userID int64) tgbotapi.ReplyKeyboardMarkup {
+	quickRow := tgbotapi.NewKeyboardButtonRow(
+		tgbotapi.NewKeyboardButton(b.t(userID, "menu.asap")),
+	)
+	if b.config.WalkInQueue {
+		quickRow = append(quickRow, tgbotapi.NewKeyboardButton(b.t(userID, "menu.walkin")))
+	}
 
-	msg := tgbotapi.NewMessage(chatID, msgText)
-	msg.ParseMode = "Markdown"
-	msg.ReplyMarkup = tgbotapi.NewReplyKeyboard(
-		tgbotapi.NewKeyboardButtonRow(
-			tgbotapi.NewKeyboardButton("📝 Записаться"),
-			tgbotapi.NewKeyboardButton("🕒 Расписание"),
-		),
+	lastRow := tgbotapi.NewKeyboardButtonRow(tgbotapi.NewKeyboardButton(b.t(userID, "menu.cancel")))
+	if b.loyaltyEnabled() {
+		lastRow = append(lastRow, tgbotapi.NewKeyboardButton(b.t(userID, "menu.balance")))
+	}
+	lastRow = append(lastRow, tgbotapi.NewKeyboardButton(b.t(userID, "menu.help")))
+
+	return tgbotapi.NewReplyKeyboard(
+		quickRow,
 		tgbotapi.NewKeyboardButtonRow(
-			tgbotapi.NewKeyboardButton("❌ Отменить запись"),
-			tgbotapi.NewKeyboardButton("ℹ️ Помощь"),
+			tgbotapi.NewKeyboardButton(b.t(userID, "menu.book")),
+			tgbotapi.NewKeyboardButton(b.t(userID, "menu.schedule")),
 		),
+		lastRow,
 	)
-	b.sendMessageWithSave(chatID, msg)
 }
 
-func (b *CarWashBot) handleTimeSelection(chatID, userID int64, timeStr string) {
-	state := b.userStates[userID]
-	now := time.Now()
-	today := now.Format("02.01.2006")
-	if state.SelectedDate == today {
-		selectedTime, err := time.Parse("15:04", timeStr)
-		if err == nil {
-			// Получаем текущее время + 1 час (чтобы нельзя было записаться прямо сейчас)
-			currentTime := now.Add(time.Hour)
-			selectedDateTime := time.Date(
-				now.Year(), now.Month(), now.Day(),
-				selectedTime.Hour(), selectedTime.Minute(), 0, 0, now.Location())
-
-			if selectedDateTime.Before(currentTime) {
-				b.sendMessage(chatID, "❌ Нельзя записаться на прошедшее время")
-				b.showTimeSlots(chatID, state.SelectedDate)
-				return
-			}
-		}
+func (b *CarWashBot) handleTimeSelection(chatID, userID int64, messageID int, timeStr string) {
+	state := b.flow.Data(userID)
+
+	// Проверяем правила записи (минимальное время до начала, горизонт)
+	if err := b.checkBookingPolicy(userID, state.SelectedDate, timeStr); err != nil {
+		b.sendMessage(chatID, b.errorText(userID, err))
+		b.showTimeSlots(chatID, userID, 0, state.SelectedDate)
+		return
 	}
-	// Проверяем доступность времени
-	available, err := b.storage.IsTimeAvailable(state.SelectedDate, timeStr)
-	if err != nil || !available {
-		b.sendMessage(chatID, "❌ Это время уже занято! Выберите другое время.")
-		b.showTimeSlots(chatID, state.SelectedDate)
+
+	// При переносе данные авто уже есть - сразу переносим запись
+	if state.RescheduleID != "" {
+		b.completeReschedule(chatID, userID, state.SelectedDate, timeStr)
 		return
 	}
 
-	b.userStates[userID] = models.UserState{
-		AwaitingCarInfo: true,
-		SelectedDate:    state.SelectedDate,
-		SelectedTime:    timeStr,
+	// Проверяем доступность времени
+	if !b.isSlotFreeFor(userID, state.SelectedDate, timeStr, state.SelectedService) {
+		b.showSlotTaken(chatID, state.SelectedDate, timeStr)
+		return
 	}
 
-	msg := tgbotapi.NewMessage(chatID, "Введите марку и номер машины через пробел\nПример: Лада 123")
-	b.sendMessageWithSave(chatID, msg)
+	// Данные авто, промокод, списание баллов и отказ от телефона, выбранные до смены времени, сохраняются
+	state.SelectedTime = timeStr
+	if state.CarNumber != "" {
+		if b.setState(chatID, userID, stateConfirming, state) {
+			b.showBookingSummary(chatID, userID, messageID)
+		}
+		return
+	}
+	b.flow.SetData(userID, state)
+	b.askCarClassOrInfo(chatID, userID, messageID)
 }
 
 func (b *CarWashBot) handleCarInfoInput(chatID, userID int64, text string) {
 	// Удаляем предыдущее сообщение
 	b.deleteLastMessage(chatID)
 
-	// Упрощенная проверка - просто разделяем по первому пробелу
-	parts := strings.SplitN(text, " ", 2)
-	if len(parts) < 2 {
-		msg := tgbotapi.NewMessage(chatID, "Нужно ввести и марку, и номер!\nПример: Газель 123")
-		b.sendMessageWithSave(chatID, msg)
+	// Номер хранится в каноническом виде, марка - как ввёл пользователь
+	carModel, plate, err := services.ParseCarInfo(text)
+	if err != nil {
+		hint := b.t(userID, "car.bad_plate")
+		if errors.Is(err, services.ErrNoModel) {
+			hint = b.t(userID, "car.no_model")
+		}
+		hint += "\n\n" + b.t(userID, "car.example")
+		b.sendMessageWithSave(chatID, tgbotapi.NewMessage(chatID, hint))
+		return
+	}
+
+	state := b.flow.Data(userID)
+	state.CarModel = carModel
+	state.CarNumber = plate.Number
+
+	if _, _, ok := b.checkBookingState(chatID, userID, state); !ok {
+		return
+	}
+
+	if b.needsPhone(userID, state) {
+		if b.setState(chatID, userID, stateEnteringPhone, state) {
+			b.askPhone(chatID)
+		}
+		return
+	}
+
+	if !b.setState(chatID, userID, stateConfirming, state) {
+		return
+	}
+	b.showBookingSummary(chatID, userID, 0)
+}
+
+// checkBookingState проверяет, что выбранное время всё ещё можно занять.
+// Если нельзя, сценарий сбрасывается и пользователю объясняется причина
+func (b *CarWashBot) checkBookingState(chatID, userID int64, state models.UserState) (bay int, washerID int64, ok bool) {
+	// Пока пользователь вводил данные, время могло перестать подходить под правила
+	if err := b.checkBookingPolicy(userID, state.SelectedDate, state.SelectedTime); err != nil {
+		b.flow.Reset(userID)
+		b.sendMessage(chatID, b.errorText(userID, err))
+		b.showDaySelection(chatID)
+		return 0, 0, false
+	}
+
+	bay, washerID = b.findSlotPlace(userID, state.SelectedDate, state.SelectedTime, state.SelectedService, "")
+	if bay == 0 {
+		b.flow.Reset(userID)
+		b.showSlotTaken(chatID, state.SelectedDate, state.SelectedTime)
+		return 0, 0, false
+	}
+
+	if err := b.checkQuota(userID, state.SelectedDate, state.CarNumber); err != nil {
+		b.flow.Reset(userID)
+		b.sendMessage(chatID, b.errorText(userID, err))
+		return 0, 0, false
+	}
+	return bay, washerID, true
+}
+
+// createBooking сохраняет запись после подтверждения пользователем
+func (b *CarWashBot) createBooking(chatID, userID int64) {
+	state := b.flow.Data(userID)
+	carModel, carNumber := state.CarModel, state.CarNumber
+
+	bay, washerID, ok := b.checkBookingState(chatID, userID, state)
+	if !ok {
+		return
+	}
+
+	// Цена фиксируется в записи, чтобы изменения прайс-листа не меняли историю
+	price, err := b.slotPrice(state.SelectedDate, state.SelectedTime, state.SelectedService, state.SelectedClass)
+	if err != nil {
+		log.Printf("Ошибка расчёта цены: %v", err)
+	}
+
+	// Баланс мог измениться, пока клиент смотрел итог записи
+	loyaltyPoints := 0
+	if state.UseReward {
+		if !b.canRedeemReward(userID) {
+			state.UseReward = false
+			b.flow.SetData(userID, state)
+			b.sendMessage(chatID, b.t(userID, "loyalty.not_enough"))
+			b.showBookingSummary(chatID, userID, 0)
+			return
+		}
+		price, loyaltyPoints = 0, b.config.LoyaltyRewardPoints
+	}
+
+	discount, err := b.promoDiscount(userID, state, price)
+	if err != nil {
+		state.PromoCode = ""
+		b.flow.SetData(userID, state)
+		b.sendMessage(chatID, b.promoError(userID, err))
+		b.showBookingSummary(chatID, userID, 0)
 		return
 	}
+	price -= discount
 
-	carModel := parts[0]
-	carNumber := parts[1]
-	state := b.userStates[userID]
+	// Клиентам с неявками запись может требовать подтверждения администратора,
+	// а запись на часы с наценкой - предоплаты
+	status := models.BookingActive
+	switch {
+	case b.needsConfirmation(userID):
+		status = models.BookingPending
+	case b.needsPrepayment(userID, state, price):
+		status = models.BookingAwaitingPayment
+	}
 
 	// Записываем в расписание
-	err := b.storage.AddBooking(models.Booking{
-		ID:        fmt.Sprintf("%d-%s-%s", userID, state.SelectedDate, state.SelectedTime),
+	duration, buffer := b.bookingTiming(state.SelectedService, bay)
+	newBooking := models.Booking{
+		ID:        b.newBookingID(userID, state.SelectedDate, state.SelectedTime),
 		Date:      state.SelectedDate,
 		Time:      state.SelectedTime,
 		CarModel:  carModel,
 		CarNumber: carNumber,
 		UserID:    userID,
 		Created:   time.Now(),
-	})
+		Service:   state.SelectedService,
+		CarClass:  state.SelectedClass,
+		Price:     price,
+		Duration:  duration,
+		Buffer:    buffer,
+		Bay:       bay,
+		WasherID:  washerID,
+		Status:    status,
+
+		LoyaltyPoints: loyaltyPoints,
+		PromoCode:     state.PromoCode,
+		Discount:      discount,
+	}
+	bookingID := newBooking.ID
+	err = b.storage.AddBooking(newBooking)
 	if err != nil {
-		b.sendMessage(chatID, "⚠️ Ошибка при сохранении записи")
+		b.sendMessage(chatID, b.t(userID, "booking.save_error"))
 		return
 	}
 
+	b.logBookingEvent(models.Booking{ID: bookingID, UserID: userID}, models.EventCreated,
+		state.SelectedDate+" "+state.SelectedTime, userID)
+	if loyaltyPoints > 0 {
+		b.addLoyaltyEntry(userID, bookingID, -loyaltyPoints, models.LoyaltyRedeem, "", userID)
+	}
+	// Промокод из ссылки использован, в следующую запись он не подставляется
+	if state.PromoCode != "" && state.PromoCode == b.pendingPromo(userID) {
+		if err := b.storage.SetCustomerPromo(userID, ""); err != nil {
+			log.Printf("Ошибка сброса промокода: %v", err)
+		}
+	}
+	b.flow.Reset(userID)
+
+	if status == models.BookingAwaitingPayment {
+		b.requestPrepayment(chatID, newBooking)
+		return
+	}
+	b.finishBooking(chatID, newBooking)
+}
+
+// finishBooking подтверждает клиенту сохранённую запись и оповещает администраторов.
+// Запись с предоплатой завершается после оплаты
+func (b *CarWashBot) finishBooking(chatID int64, booking models.Booking) {
+	userID := booking.UserID
+	b.scheduleBookingJobs(booking)
+
+	// Запись создана - лист ожидания на этот день пользователю больше не нужен
+	if err := b.storage.CompleteWaitlistEntries(userID, booking.Date); err != nil {
+		log.Printf("Ошибка обновления листа ожидания: %v", err)
+	}
+
 	if b.config.ChannelID != 0 {
-		booking, err := b.storage.GetBookingByID(fmt.Sprintf("%d-%s-%s", userID, state.SelectedDate, state.SelectedTime))
-		if err != nil {
-			log.Printf("Ошибка получения записи: %v", err)
-		} else if booking != nil {
-			if err := b.notifyChannel(*booking); err != nil {
-				log.Printf("Ошибка оповещения канала: %v", err)
-				b.sendMessage(b.adminID, fmt.Sprintf("Ошибка отправки в канал: %v", err))
-			}
+		if err := b.notifyChannel(booking); err != nil {
+			log.Printf("Ошибка оповещения канала: %v", err)
+			b.sendMessage(b.adminID, fmt.Sprintf("Ошибка отправки в канал: %v", err))
 		}
 	}
-	delete(b.userStates, userID)
 
 	// Отправляем подтверждение
-	confirmMsg := fmt.Sprintf(`✅ Вы успешно записаны на мойку!
-
-	📅 Дата: %s
-	🕒 Время: %s
-	🚗 Автомобиль: %s %s
-	
-	Спасибо за выбор нашей услуги!`,
-		state.SelectedDate, state.SelectedTime, carModel, carNumber)
+	confirmKey := "booking.created"
+	if booking.Status == models.BookingPending {
+		confirmKey = "booking.pending"
+	}
+	confirmMsg := b.t(userID, confirmKey,
+		booking.Date, booking.Time, booking.CarModel, booking.CarNumber,
+		b.describeService(booking), b.pricing.FormatPrice(booking.Price))
 
 	msg := tgbotapi.NewMessage(chatID, confirmMsg)
 	msg.ReplyMarkup = tgbotapi.NewReplyKeyboard(
 		tgbotapi.NewKeyboardButtonRow(
-			tgbotapi.NewKeyboardButton("🏠 Главное меню"),
+			tgbotapi.NewKeyboardButton(b.t(userID, "menu.main")),
 		),
 	)
 	b.sendMessageWithSave(chatID, msg)
 
+	b.sendBookingCalendar(chatID, booking)
+
 	// Уведомляем админа
-	b.notifyAdminAboutNewBooking(state.SelectedTime, carModel, carNumber)
+	b.notifyAdminAboutNewBooking(booking)
+
+	if booking.Status == models.BookingActive {
+		b.offerRecurring(chatID, booking.ID)
+	}
 }
+
 func (b *CarWashBot) showSchedule(chatID int64) {
-	// Русские названия дней недели и месяцев
-	weekdayNames := map[time.Weekday]string{
-		time.Monday:    "Понедельник",
-		time.Tuesday:   "Вторник",
-		time.Wednesday: "Среда",
-		time.Thursday:  "Четверг",
-		time.Friday:    "Пятница",
-		time.Saturday:  "Суббота",
-		time.Sunday:    "Воскресенье",
-	}
-
-	monthNames := map[time.Month]string{
-		time.January:   "Января",
-		time.February:  "Февраля",
-		time.March:     "Марта",
-		time.April:     "Апреля",
-		time.May:       "Мая",
-		time.June:      "Июня",
-		time.July:      "Июля",
-		time.August:    "Августа",
-		time.September: "Сентября",
-		time.October:   "Октября",
-		time.November:  "Ноября",
-		time.December:  "Декабря",
-	}
+	lang := b.lang(chatID)
 
 	allBookings, err := b.storage.GetAllBookings()
 	if err != nil {
-		b.sendMessage(chatID, "⚠️ Ошибка при получении расписания")
+		b.sendMessage(chatID, i18n.T(lang, "schedule.error"))
 		return
 	}
 	bookingsByDate := make(map[string][]models.Booking)
@@ -275,7 +582,7 @@ func (b *CarWashBot) showSchedule(chatID int64) {
 	})
 
 	var sb strings.Builder
-	sb.WriteString("📅 *Расписание моек*\n\n")
+	sb.WriteString(i18n.T(lang, "schedule.title"))
 
 	now := time.Now()
 	today := now.Format("02.01.2006")
@@ -283,17 +590,16 @@ func (b *CarWashBot) showSchedule(chatID int64) {
 
 	for _, date := range dates {
 		dateStr := date.Format("02.01.2006")
-		dayName := weekdayNames[date.Weekday()]
-		monthName := monthNames[date.Month()]
+		dayMonth := i18n.DayMonth(lang, date)
 
 		// Форматируем заголовок
 		switch dateStr {
 		case today:
-			sb.WriteString(fmt.Sprintf("=== Сегодня, %d %s ===\n", date.Day(), monthName))
+			sb.WriteString(i18n.T(lang, "schedule.today", dayMonth))
 		case tomorrow:
-			sb.WriteString(fmt.Sprintf("=== Завтра, %d %s ===\n", date.Day(), monthName))
+			sb.WriteString(i18n.T(lang, "schedule.tomorrow", dayMonth))
 		default:
-			sb.WriteString(fmt.Sprintf("=== %s, %d %s ===\n", dayName, date.Day(), monthName))
+			sb.WriteString(i18n.T(lang, "schedule.day", i18n.Weekday(lang, date.Weekday()), dayMonth))
 		}
 
 		// Сортируем записи по времени
@@ -313,26 +619,42 @@ func (b *CarWashBot) showSchedule(chatID int64) {
 	}
 
 	if len(dates) == 0 {
-		sb.WriteString("На данный момент нет записей\n")
+		sb.WriteString(i18n.T(lang, "schedule.empty"))
 	}
 
 	msg := tgbotapi.NewMessage(chatID, sb.String())
 	msg.ParseMode = "Markdown"
 	msg.ReplyMarkup = tgbotapi.NewReplyKeyboard(
 		tgbotapi.NewKeyboardButtonRow(
-			tgbotapi.NewKeyboardButton("📝 Записаться"),
-			tgbotapi.NewKeyboardButton("🏠 Главное меню"),
+			tgbotapi.NewKeyboardButton(i18n.T(lang, "menu.book")),
+			tgbotapi.NewKeyboardButton(i18n.T(lang, "menu.main")),
 		),
 	)
 	b.sendMessageWithSave(chatID, msg)
 }
 
-func (b *CarWashBot) notifyAdminAboutNewBooking(timeStr, carModel, carNumber string) {
+func (b *CarWashBot) notifyAdminAboutNewBooking(booking models.Booking) {
 	msgText := fmt.Sprintf(`🆕 Новая запись:
-Время: %s
-Авто: %s %s`, timeStr, carModel, carNumber)
+Время: %s %s
+Авто: %s %s
+Услуга: %s
+Цена: %s`, booking.Date, booking.Time, booking.CarModel, booking.CarNumber,
+		b.describeService(booking), b.pricing.FormatPrice(booking.Price))
+	if phone := b.customerPhone(booking.UserID); phone != "" {
+		msgText += "\nТелефон: " + phone
+	}
 
 	msg := tgbotapi.NewMessage(b.adminID, msgText)
+	if booking.Status == models.BookingPending {
+		noShows, _ := b.storage.CountNoShows(booking.UserID)
+		msg.Text += fmt.Sprintf("\n\n⏳ Требует подтверждения: у клиента %d неявок", noShows)
+		msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+			tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData("✅ Подтвердить", "admin_confirm:"+booking.ID),
+				tgbotapi.NewInlineKeyboardButtonData("❌ Отклонить", "admin_reject:"+booking.ID),
+			),
+		)
+	}
 	b.botAPI.Send(msg)
 }
 
@@ -355,6 +677,67 @@ func (b *CarWashBot) sendMessageWithSave(chatID int64, msg tgbotapi.MessageConfi
 	b.msgIDLock.Unlock()
 }
 
+// showScreen показывает экран с inline-кнопками: если передан messageID, редактирует
+// это сообщение, иначе (или если отредактировать не вышло) отправляет новое.
+// Показанное сообщение становится текущим экраном чата
+func (b *CarWashBot) showScreen(chatID int64, messageID int, msg tgbotapi.MessageConfig) {
+	markup, isInline := msg.ReplyMarkup.(tgbotapi.InlineKeyboardMarkup)
+	if messageID != 0 && (isInline || msg.ReplyMarkup == nil) {
+		editMsg := tgbotapi.NewEditMessageText(chatID, messageID, msg.Text)
+		editMsg.ParseMode = msg.ParseMode
+		if isInline {
+			editMsg.ReplyMarkup = &markup
+		}
+		_, err := b.botAPI.Send(editMsg)
+		if err == nil || strings.Contains(err.Error(), "message is not modified") {
+			b.msgIDLock.Lock()
+			b.lastMessageID[chatID] = messageID
+			b.msgIDLock.Unlock()
+			return
+		}
+		log.Printf("Не удалось обновить сообщение %d: %v", messageID, err)
+	}
+
+	b.sendMessageWithSave(chatID, msg)
+}
+
+// isScreenCallback - кнопки экранов записи, которые действуют только в последнем экране чата
+func isScreenCallback(data string) bool {
+	for _, prefix := range []string{"svc_", "cal_", "day_", "time_", "cls_", "book_", "asap_", "rec_", "back_to_dates"} {
+		if strings.HasPrefix(data, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// isStaleScreen проверяет, что кнопка нажата не в последнем экране чата
+func (b *CarWashBot) isStaleScreen(chatID int64, messageID int) bool {
+	b.msgIDLock.Lock()
+	defer b.msgIDLock.Unlock()
+	return b.lastMessageID[chatID] != messageID
+}
+
+// refreshStaleScreen убирает кнопки у устаревшего экрана вместо выполнения действия.
+// Если актуального экрана нет (например, бот перезапускался), показывает главное меню
+func (b *CarWashBot) refreshStaleScreen(query *tgbotapi.CallbackQuery) {
+	chatID := query.Message.Chat.ID
+	b.answerCallback(query.ID, b.t(query.From.ID, "screen.stale"), true)
+
+	editMarkup := tgbotapi.NewEditMessageReplyMarkup(chatID, query.Message.MessageID,
+		tgbotapi.InlineKeyboardMarkup{InlineKeyboard: [][]tgbotapi.InlineKeyboardButton{}})
+	if _, err := b.botAPI.Request(editMarkup); err != nil {
+		log.Printf("Ошибка обновления устаревшего меню: %v", err)
+	}
+
+	b.msgIDLock.Lock()
+	current := b.lastMessageID[chatID]
+	b.msgIDLock.Unlock()
+	if current == 0 {
+		b.sendWelcomeMessage(chatID)
+	}
+}
+
 func (b *CarWashBot) deleteLastMessage(chatID int64) {
 	b.msgIDLock.Lock()
 	msgID := b.lastMessageID[chatID]
@@ -365,44 +748,33 @@ func (b *CarWashBot) deleteLastMessage(chatID int64) {
 		b.botAPI.Request(deleteMsg)
 	}
 }
-func (b *CarWashBot) showTimeSlots(chatID int64, dateStr string) {
-	now := time.Now()
-	todayStr := now.Format("02.01.2006")
 
+// showTimeSlots показывает время на день. Если передан messageID, экран выбора дня
+// заменяется в том же сообщении
+func (b *CarWashBot) showTimeSlots(chatID, userID int64, messageID int, dateStr string) {
+	lang := b.lang(userID)
 	date, err := time.Parse("02.01.2006", dateStr)
 	if err != nil {
-		b.sendMessage(chatID, "Ошибка формата даты")
+		b.sendMessage(chatID, i18n.T(lang, "day.bad_format"))
 		return
 	}
 
-	weekdayNames := [...]string{
-		"Воскресенье", "Понедельник", "Вторник",
-		"Среда", "Четверг", "Пятница", "Суббота",
-	}
-
-	header := fmt.Sprintf("Выберите время на %s, %s:",
-		weekdayNames[date.Weekday()],
-		date.Format("02.01.2006"))
+	header := i18n.T(lang, "time.header", i18n.Weekday(lang, date.Weekday()), date.Format("02.01.2006"))
 
 	var rows [][]tgbotapi.InlineKeyboardButton
+	service := b.flow.Data(userID).SelectedService
+	open := b.openSlotTimes(dateStr, service)
 
-	for hour := b.storage.StartTime; hour <= b.storage.EndTime; hour++ {
-		timeStr := fmt.Sprintf("%02d:00", hour)
-		available, err := b.storage.IsTimeAvailable(dateStr, timeStr)
-		if err != nil {
-			available = false
-		}
-
-		// Если это сегодня и время уже прошло
-		if dateStr == todayStr && hour <= now.Hour() {
-			available = false
-		}
+	for _, timeStr := range b.slotTimes() {
+		available := open[timeStr]
 
-		btnText := fmt.Sprintf("🕒 %s", timeStr)
+		var btnText string
 		if !available {
-			btnText = "🔴 " + timeStr + " (Недоступно)"
+			btnText = i18n.T(lang, "time.unavailable", timeStr)
+		} else if price, err := b.slotPrice(dateStr, timeStr, service, ""); err == nil {
+			btnText = "🟢 " + timeStr + " · " + b.pricing.FormatPrice(price)
 		} else {
-			btnText = "🟢 " + timeStr + " (Свободно)"
+			btnText = i18n.T(lang, "time.free", timeStr)
 		}
 
 		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
@@ -412,67 +784,42 @@ func (b *CarWashBot) showTimeSlots(chatID int64, dateStr string) {
 
 	// Добавляем кнопки навигации
 	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
-		tgbotapi.NewInlineKeyboardButtonData("🔙 Назад к выбору даты", "back_to_dates"),
-		tgbotapi.NewInlineKeyboardButtonData("🏠 Главное меню", "main_menu"),
+		tgbotapi.NewInlineKeyboardButtonData(i18n.T(lang, "time.back_to_dates"), "back_to_dates"),
+		tgbotapi.NewInlineKeyboardButtonData(i18n.T(lang, "menu.main"), "main_menu"),
 	))
 
 	msg := tgbotapi.NewMessage(chatID, header)
 	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(rows...)
-	b.sendMessageWithSave(chatID, msg)
+	b.showScreen(chatID, messageID, msg)
 }
-func (b *CarWashBot) showDaySelection(chatID int64) {
-	now := time.Now()
-	var buttons [][]tgbotapi.InlineKeyboardButton
-
-	weekdayNames := []string{
-		"Воскресенье", "Понедельник", "Вторник",
-		"Среда", "Четверг", "Пятница", "Суббота",
-	}
-
-	for i := 0; i < 7; i++ {
-		date := now.AddDate(0, 0, i)
-		dateStr := date.Format("02.01.2006")
-		weekday := weekdayNames[date.Weekday()]
-
-		dayDesc := ""
-		switch i {
-		case 0:
-			dayDesc = "Сегодня"
-		case 1:
-			dayDesc = "Завтра"
-		default:
-			dayDesc = weekday
-		}
-
-		btnText := fmt.Sprintf("📅 %s (%s)", dayDesc, date.Format("02.01"))
-		buttons = append(buttons, tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData(btnText, "day_"+dateStr),
-		))
-	}
-
-	buttons = append(buttons, tgbotapi.NewInlineKeyboardRow(
-		tgbotapi.NewInlineKeyboardButtonData("🏠 Главное меню", "main_menu"),
-	))
-
-	msg := tgbotapi.NewMessage(chatID, "Выберите день для записи:")
-	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(buttons...)
-	b.sendMessageWithSave(chatID, msg)
-}
-func (b *CarWashBot) handleDaySelection(chatID, userID int64, dateStr string) {
+func (b *CarWashBot) handleDaySelection(chatID, userID int64, messageID int, dateStr string) {
 	now := time.Now()
 	todayStr := now.Format("02.01.2006")
 
 	// Парсим выбранную дату
 	selectedDate, err := time.Parse("02.01.2006", dateStr)
 	if err != nil {
-		b.sendMessage(chatID, "❌ Ошибка формата даты")
+		b.sendMessage(chatID, b.t(userID, "day.bad_format"))
 		b.showDaySelection(chatID)
 		return
 	}
 
 	// Проверяем, что дата не в прошлом (используем parsedDate)
 	if selectedDate.Before(now.Truncate(24 * time.Hour)) {
-		b.sendMessage(chatID, "❌ Нельзя записаться на прошедшую дату")
+		b.sendMessage(chatID, b.t(userID, "day.past"))
+		b.showDaySelection(chatID)
+		return
+	}
+
+	if !b.isAdmin(userID) && !b.isWithinHorizon(selectedDate) {
+		days := b.bookingPolicy().MaxAdvanceDays
+		b.sendMessage(chatID, b.tn(userID, "day.horizon", days, days))
+		b.showDaySelection(chatID)
+		return
+	}
+
+	if b.isDayClosed(selectedDate) {
+		b.sendMessage(chatID, b.t(userID, "day.closed"))
 		b.showDaySelection(chatID)
 		return
 	}
@@ -481,54 +828,98 @@ func (b *CarWashBot) handleDaySelection(chatID, userID int64, dateStr string) {
 	if dateStr == todayStr {
 		currentHour := now.Hour()
 		if currentHour >= b.storage.EndTime {
-			b.sendMessage(chatID, "❌ На сегодня время записи уже закончилось")
+			b.sendMessage(chatID, b.t(userID, "day.too_late"))
 			b.showDaySelection(chatID)
 			return
 		}
 	}
 
-	b.userStates[userID] = models.UserState{
-		AwaitingTime: true,
-		SelectedDate: dateStr,
+	if b.freeSlotsCount(dateStr, b.flow.Data(userID).SelectedService) == 0 {
+		b.sendMessage(chatID, b.t(userID, "day.full"))
+		b.showDaySelection(chatID)
+		return
 	}
 
-	b.showTimeSlots(chatID, dateStr)
+	prevState := b.flow.Data(userID)
+	rescheduleID := prevState.RescheduleID
+	if err := b.checkDayQuota(userID, rescheduleID, dateStr); err != nil {
+		b.sendMessage(chatID, b.errorText(userID, err))
+		b.showDaySelection(chatID)
+		return
+	}
+
+	if !b.setState(chatID, userID, stateChoosingTime, models.UserState{
+		SelectedDate:    dateStr,
+		SelectedService: prevState.SelectedService,
+		SelectedClass:   prevState.SelectedClass,
+		CarModel:        prevState.CarModel,
+		CarNumber:       prevState.CarNumber,
+		RescheduleID:    rescheduleID,
+	}) {
+		return
+	}
+
+	b.showTimeSlots(chatID, userID, messageID, dateStr)
 }
 func (b *CarWashBot) showUserBookings(chatID, userID int64) {
 	bookings, err := b.storage.GetUserBookings(userID)
 	if err != nil {
-		b.sendMessage(chatID, "⚠️ Ошибка при получении ваших записей")
+		b.sendMessage(chatID, b.t(userID, "bookings.error"))
 		return
 	}
 
 	if len(bookings) == 0 {
-		b.sendMessage(chatID, "У вас нет активных записей.")
+		b.sendMessage(chatID, b.t(userID, "bookings.none"))
 		return
 	}
 
 	var sb strings.Builder
-	sb.WriteString("📋 *Ваши записи:*\n\n")
+	sb.WriteString(b.t(userID, "bookings.title"))
 
 	var buttons [][]tgbotapi.InlineKeyboardButton
+	seriesShown := make(map[int64]bool)
 
 	for _, booking := range bookings {
 		sb.WriteString(fmt.Sprintf(
-			"📅 %s\n🕒 %s\n🚗 %s %s\n\n",
+			"📅 %s\n🕒 %s\n🚗 %s %s\n",
 			booking.Date,
 			booking.Time,
 			booking.CarModel,
 			booking.CarNumber,
 		))
+		if booking.Price > 0 {
+			sb.WriteString(fmt.Sprintf("💰 %s\n", b.pricing.FormatPrice(booking.Price)))
+		}
+		switch booking.Status {
+		case models.BookingPending:
+			sb.WriteString(b.t(userID, "bookings.pending"))
+		case models.BookingAwaitingPayment:
+			sb.WriteString(b.t(userID, "bookings.awaiting_payment"))
+		}
+		sb.WriteString("\n")
 
-		// Добавляем кнопку отмены для каждой записи
-		btnData := fmt.Sprintf("cancel_%s_%s", booking.Date, booking.Time)
-		buttons = append(buttons, tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("❌ Отменить эту запись", btnData),
-		))
+		// Добавляем кнопку отмены для каждой записи. Неоплаченную запись можно только
+		// отменить: переносить время, которое держится до оплаты, нет смысла
+		btnText := b.t(userID, "bookings.cancel", booking.Date, booking.Time)
+		row := tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(btnText, "cancel_"+booking.ID))
+		if booking.Status != models.BookingAwaitingPayment {
+			row = append(row, tgbotapi.NewInlineKeyboardButtonData(b.t(userID, "bookings.reschedule"), "resched_"+booking.ID))
+		}
+		buttons = append(buttons, row)
+
+		// Для регулярной записи - одна кнопка отмены всей серии
+		if booking.SeriesID != 0 && !seriesShown[booking.SeriesID] {
+			seriesShown[booking.SeriesID] = true
+			buttons = append(buttons, tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData(
+					b.t(userID, "bookings.cancel_series", booking.Time),
+					fmt.Sprintf("series_cancel_%d", booking.SeriesID)),
+			))
+		}
 	}
 
 	buttons = append(buttons, tgbotapi.NewInlineKeyboardRow(
-		tgbotapi.NewInlineKeyboardButtonData("🏠 В главное меню", "main_menu"),
+		tgbotapi.NewInlineKeyboardButtonData(b.t(userID, "button.back_to_menu"), "main_menu"),
 	))
 
 	msg := tgbotapi.NewMessage(chatID, sb.String())
@@ -539,7 +930,7 @@ func (b *CarWashBot) showUserBookings(chatID, userID int64) {
 func (b *CarWashBot) handleCancelCommand(chatID, userID int64) {
 	userBookings, _ := b.storage.GetUserBookings(userID)
 	if len(userBookings) == 0 {
-		b.sendMessage(chatID, "У вас нет активных записей.")
+		b.sendMessage(chatID, b.t(userID, "bookings.none"))
 		return
 	}
 
@@ -553,10 +944,10 @@ func (b *CarWashBot) handleCancelCommand(chatID, userID int64) {
 	}
 
 	buttons = append(buttons, tgbotapi.NewInlineKeyboardRow(
-		tgbotapi.NewInlineKeyboardButtonData("🔙 Назад", "main_menu"),
+		tgbotapi.NewInlineKeyboardButtonData(b.t(userID, "button.back"), "main_menu"),
 	))
 
-	msg := tgbotapi.NewMessage(chatID, "Выберите запись для отмены:")
+	msg := tgbotapi.NewMessage(chatID, b.t(userID, "cancel.choose"))
 	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(buttons...)
 	b.sendMessageWithSave(chatID, msg)
 }
@@ -564,28 +955,49 @@ func (b *CarWashBot) handleCancelCommand(chatID, userID int64) {
 func (b *CarWashBot) handleBookingCancellation(chatID, userID int64, bookingID string) {
 	booking, err := b.storage.GetBookingByID(bookingID)
 	if err != nil {
-		b.sendMessage(chatID, "❌ Ошибка при отмене записи")
+		b.sendMessage(chatID, b.t(userID, "cancel.error"))
 		return
 	}
 
 	if booking == nil {
-		b.sendMessage(chatID, "❌ Запись не найдена")
+		b.sendMessage(chatID, b.t(userID, "cancel.not_found"))
+		return
+	}
+
+	if booking.UserID != userID && !b.isAdmin(userID) {
+		b.sendMessage(chatID, b.t(userID, "cancel.not_yours"))
+		return
+	}
+
+	if err := b.checkChangePolicy(userID, *booking); err != nil {
+		b.sendMessage(chatID, b.errorText(userID, err))
+		return
+	}
+	// Предоплату возвращает администратор, поэтому оплаченную запись отменяет только он
+	if !b.isAdmin(userID) && b.bookingPayment(*booking) != nil {
+		b.sendMessage(chatID, b.t(userID, "payment.cancel_paid"))
 		return
 	}
 
 	err = b.storage.DeleteBooking(bookingID)
 	if err != nil {
-		b.sendMessage(chatID, "❌ Не удалось отменить запись")
+		b.sendMessage(chatID, b.t(userID, "cancel.failed"))
 		return
 	}
+	b.logBookingEvent(*booking, models.EventCancelled, booking.Date+" "+booking.Time, userID)
+	b.refundLoyaltyPoints(*booking, userID)
+	b.refundPrepayment(*booking, userID)
 
-	msg := fmt.Sprintf("✅ Запись отменена:\n📅 %s\n🕒 %s\n🚗 %s %s",
+	msg := b.t(userID, "cancel.done",
 		booking.Date,
 		booking.Time,
 		booking.CarModel,
 		booking.CarNumber)
 	b.sendMessage(chatID, msg)
 
+	// Предлагаем освободившееся время листу ожидания
+	b.offerFreedSlot(booking.Date, booking.Time)
+
 	// Уведомление администратора
 	if userID != b.adminID {
 		adminMsg := fmt.Sprintf("ℹ️ Пользователь отменил запись:\n%s %s - %s %s",
@@ -0,0 +1,157 @@
+package bot
+
+import (
+	"carwash-bot/internal/models"
+	"carwash-bot/internal/services"
+	"crypto/subtle"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Сколько дней прошедших записей остаётся в календаре для персонала
+const calendarFeedHistoryDays = 30
+
+// bookingCalendarEvent превращает запись в событие календаря
+func (b *CarWashBot) bookingCalendarEvent(booking models.Booking, summary string) (services.CalendarEvent, error) {
+	start, err := booking.StartsAt()
+	if err != nil {
+		return services.CalendarEvent{}, err
+	}
+	duration := booking.Duration
+	if duration <= 0 {
+		duration = services.DefaultServiceDuration
+	}
+
+	location := b.config.LocationName
+	if b.config.LocationAddress != "" {
+		location += ", " + b.config.LocationAddress
+	}
+
+	description := fmt.Sprintf("%s %s", booking.CarModel, booking.CarNumber)
+	if service := b.describeService(booking); service != "" {
+		description += "\n" + service
+	}
+	if booking.Price > 0 {
+		description += "\n" + b.pricing.FormatPrice(booking.Price)
+	}
+
+	return services.CalendarEvent{
+		UID:         booking.ID + "@" + b.config.LocationID,
+		Start:       start,
+		End:         start.Add(time.Duration(duration) * time.Minute),
+		Summary:     summary,
+		Description: description,
+		Location:    location,
+		Tentative:   booking.Status == models.BookingPending,
+	}, nil
+}
+
+// sendBookingCalendar отправляет клиенту файл .ics, чтобы добавить запись в календарь телефона
+func (b *CarWashBot) sendBookingCalendar(chatID int64, booking models.Booking) {
+	event, err := b.bookingCalendarEvent(booking, "🚗 Мойка: "+booking.CarModel+" "+booking.CarNumber)
+	if err != nil {
+		log.Printf("Ошибка формирования события календаря: %v", err)
+		return
+	}
+
+	file := tgbotapi.FileBytes{
+		Name:  fmt.Sprintf("carwash-%s.ics", strings.ReplaceAll(booking.Date, ".", "-")),
+		Bytes: services.BuildCalendar(b.config.LocationName, []services.CalendarEvent{event}, time.Now()),
+	}
+	doc := tgbotapi.NewDocument(chatID, file)
+	doc.Caption = "📅 Откройте файл, чтобы добавить запись в календарь"
+	if _, err := b.botAPI.Send(doc); err != nil {
+		log.Printf("Ошибка отправки файла календаря: %v", err)
+	}
+}
+
+// calendarFeedPath - путь календаря мойки на HTTP-сервере
+func (b *CarWashBot) calendarFeedPath() string {
+	return "/calendar/" + b.config.LocationID + ".ics"
+}
+
+// runCalendarFeed запускает HTTP-сервер с календарём всех записей для персонала.
+// Календарь собирается при каждом запросе, поэтому приложения видят переносы и отмены
+func (b *CarWashBot) runCalendarFeed() {
+	if b.config.CalendarToken == "" {
+		log.Printf("CALENDAR_TOKEN не задан, календарь записей не запущен")
+		return
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(b.calendarFeedPath(), b.serveCalendarFeed)
+
+	log.Printf("Календарь записей доступен на %s%s", b.config.CalendarAddr, b.calendarFeedPath())
+	if err := http.ListenAndServe(b.config.CalendarAddr, mux); err != nil {
+		log.Printf("Ошибка сервера календаря: %v", err)
+	}
+}
+
+func (b *CarWashBot) serveCalendarFeed(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	if subtle.ConstantTimeCompare([]byte(token), []byte(b.config.CalendarToken)) != 1 {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+
+	bookings, err := b.storage.GetAllBookings()
+	if err != nil {
+		log.Printf("Ошибка получения записей для календаря: %v", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	from := time.Now().AddDate(0, 0, -calendarFeedHistoryDays)
+	var events []services.CalendarEvent
+	for _, booking := range bookings {
+		if booking.Status == models.BookingNoShow {
+			continue
+		}
+		summary := fmt.Sprintf("%s %s", booking.CarModel, booking.CarNumber)
+		if booking.WasherID != 0 {
+			if staff, err := b.storage.GetStaff(booking.WasherID); err == nil && staff != nil {
+				summary += " · " + staff.Name
+			}
+		}
+		if b.config.Bays > 1 {
+			summary = fmt.Sprintf("Пост %d: %s", booking.Bay, summary)
+		}
+
+		event, err := b.bookingCalendarEvent(booking, summary)
+		if err != nil || event.End.Before(from) {
+			continue
+		}
+		events = append(events, event)
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Write(services.BuildCalendar(b.config.LocationName+" — записи", events, time.Now()))
+}
+
+// handleCalendarCommand выдаёт персоналу ссылку для подписки на календарь записей
+func (b *CarWashBot) handleCalendarCommand(chatID, userID int64) {
+	if !b.isAdmin(userID) {
+		staff, err := b.storage.GetStaffByTelegramID(userID)
+		if err != nil || staff == nil {
+			b.sendMessage(chatID, "❌ Команда доступна только персоналу")
+			return
+		}
+	}
+
+	if b.config.CalendarAddr == "" || b.config.CalendarToken == "" || b.config.CalendarBaseURL == "" {
+		b.sendMessage(chatID, "ℹ️ Календарь записей не настроен (CALENDAR_ADDR, CALENDAR_TOKEN, CALENDAR_BASE_URL)")
+		return
+	}
+
+	url := fmt.Sprintf("%s%s?token=%s", strings.TrimSuffix(b.config.CalendarBaseURL, "/"),
+		b.calendarFeedPath(), b.config.CalendarToken)
+	b.sendMessage(chatID, "📅 Календарь всех записей:\n"+url+
+		"\n\nДобавьте ссылку в календарь телефона как подписку — записи будут обновляться сами. "+
+		"Не пересылайте ссылку клиентам.")
+}
@@ -0,0 +1,182 @@
+package bot
+
+import (
+	"carwash-bot/internal/i18n"
+	"carwash-bot/internal/models"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Параметр ссылки /start slot_<ддммгггг>_<ччмм> - запись на время из inline-результата.
+// В параметре разрешены только латиница, цифры, "_" и "-", поэтому точки и двоеточия убраны
+const (
+	slotStartPrefix = "slot_"
+	slotStartLayout = "02012006_1504"
+)
+
+// Сколько времён показывать в ответе на inline-запрос (Telegram принимает до 50 результатов)
+const inlineSlotsLimit = 30
+
+// Ответ на inline-запрос кешируется Telegram, поэтому время жизни небольшое - занятость быстро меняется
+const inlineCacheSeconds = 30
+
+// parseInlineDay разбирает день из inline-запроса: пусто, "сегодня", "завтра", "послезавтра"
+// (и то же по-английски), "дд.мм" или "дд.мм.гггг"
+func parseInlineDay(query string, now time.Time) (time.Time, bool) {
+	query = strings.ToLower(strings.TrimSpace(query))
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	switch {
+	case query == "" || i18n.Matches(query, "inline.day.today"):
+		return today, true
+	case i18n.Matches(query, "inline.day.tomorrow"):
+		return today.AddDate(0, 0, 1), true
+	case i18n.Matches(query, "inline.day.after_tomorrow"):
+		return today.AddDate(0, 0, 2), true
+	}
+
+	if date, err := time.ParseInLocation("02.01.2006", query, now.Location()); err == nil {
+		return date, true
+	}
+	if date, err := time.ParseInLocation("02.01", query, now.Location()); err == nil {
+		// Год не указан: ближайшая такая дата, не раньше сегодняшней
+		date = date.AddDate(today.Year()-date.Year(), 0, 0)
+		if date.Before(today) {
+			date = date.AddDate(1, 0, 0)
+		}
+		return date, true
+	}
+	return time.Time{}, false
+}
+
+// slotStartLink - ссылка, открывающая бота сразу на записи на указанное время
+func (b *CarWashBot) slotStartLink(dateStr, timeStr string) string {
+	start, err := models.SlotTime(dateStr, timeStr)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("https://t.me/%s?start=%s%s", b.botAPI.Self.UserName, slotStartPrefix, start.Format(slotStartLayout))
+}
+
+// handleInlineQuery показывает свободное время на день по запросу "@бот завтра",
+// чтобы им можно было поделиться в любом чате. Inline-режим включается в @BotFather (/setinline)
+func (b *CarWashBot) handleInlineQuery(query *tgbotapi.InlineQuery) {
+	b.rememberLanguage(query.From)
+	userID := query.From.ID
+
+	var results []interface{}
+	date, ok := parseInlineDay(query.Query, time.Now())
+	if ok && b.isWithinHorizon(date) && !b.isDayClosed(date) {
+		results = b.inlineSlotResults(userID, date)
+	}
+
+	answer := tgbotapi.InlineConfig{
+		InlineQueryID: query.ID,
+		Results:       results,
+		CacheTime:     inlineCacheSeconds,
+		IsPersonal:    true, // Результаты на языке пользователя
+	}
+	if len(results) == 0 {
+		answer.SwitchPMText = b.t(userID, "inline.none")
+		answer.SwitchPMParameter = "inline"
+	}
+	if _, err := b.botAPI.Request(answer); err != nil {
+		log.Printf("Ошибка ответа на inline-запрос: %v", err)
+	}
+}
+
+// inlineSlotResults - карточка со всем свободным временем дня и по карточке на каждое время
+func (b *CarWashBot) inlineSlotResults(userID int64, date time.Time) []interface{} {
+	lang := b.lang(userID)
+	dateStr := date.Format("02.01.2006")
+	service := b.pricing.DefaultService().Code
+	day := fmt.Sprintf("%s %s", i18n.Weekday(lang, date.Weekday()), date.Format("02.01"))
+
+	var times []string
+	var results []interface{}
+	var rows [][]tgbotapi.InlineKeyboardButton
+	var row []tgbotapi.InlineKeyboardButton
+	open := b.openSlotTimes(dateStr, service)
+	for _, timeStr := range b.slotTimes() {
+		if len(times) == inlineSlotsLimit {
+			break
+		}
+		if !open[timeStr] {
+			continue
+		}
+		link := b.slotStartLink(dateStr, timeStr)
+		if link == "" {
+			continue
+		}
+		times = append(times, timeStr)
+
+		title := timeStr
+		if price, err := b.slotPrice(dateStr, timeStr, service, ""); err == nil && price > 0 {
+			title += " · " + b.pricing.FormatPrice(price)
+		}
+		slot := tgbotapi.NewInlineQueryResultArticle(slotStartPrefix+strings.ReplaceAll(dateStr+"_"+timeStr, ":", ""),
+			title, b.t(userID, "inline.slot_text", day, timeStr))
+		slot.Description = b.t(userID, "inline.slot_description", day)
+		markup := tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonURL(b.t(userID, "inline.book", timeStr), link),
+		))
+		slot.ReplyMarkup = &markup
+		results = append(results, slot)
+
+		row = append(row, tgbotapi.NewInlineKeyboardButtonURL(timeStr, link))
+		if len(row) == 4 {
+			rows = append(rows, row)
+			row = nil
+		}
+	}
+	if len(row) > 0 {
+		rows = append(rows, row)
+	}
+	if len(times) == 0 {
+		return nil
+	}
+
+	summary := tgbotapi.NewInlineQueryResultArticle("day_"+date.Format("02012006"),
+		b.tn(userID, "inline.day_title", len(times), day, len(times)),
+		b.t(userID, "inline.day_text", day, strings.Join(times, ", ")))
+	summary.Description = strings.Join(times, ", ")
+	markup := tgbotapi.NewInlineKeyboardMarkup(rows...)
+	summary.ReplyMarkup = &markup
+
+	return append([]interface{}{summary}, results...)
+}
+
+// startSlotBooking начинает запись на время из ссылки slot_<ддммгггг>_<ччмм>. Ссылку можно
+// написать вручную, поэтому время проверяется так же, как при выборе кнопкой: рабочий день и сетка
+// здесь, правила записи и свободный пост - в handleTimeSelection
+func (b *CarWashBot) startSlotBooking(chatID, userID int64, slot string) {
+	start, err := time.Parse(slotStartLayout, slot)
+	if err != nil {
+		b.sendWelcomeMessage(chatID)
+		return
+	}
+	dateStr, timeStr := start.Format("02.01.2006"), start.Format("15:04")
+
+	b.flow.Reset(userID)
+	if !b.isSlotOnGrid(dateStr, timeStr) {
+		b.sendMessage(chatID, b.t(userID, "inline.slot_unavailable"))
+		b.sendWelcomeMessage(chatID)
+		return
+	}
+	if err := b.checkQuota(userID, dateStr, ""); err != nil {
+		b.sendMessage(chatID, b.errorText(userID, err))
+		return
+	}
+
+	state := models.UserState{
+		SelectedDate:    dateStr,
+		SelectedService: b.pricing.DefaultService().Code,
+	}
+	if b.setState(chatID, userID, stateChoosingTime, state) {
+		b.handleTimeSelection(chatID, userID, 0, timeStr)
+	}
+}
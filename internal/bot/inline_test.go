@@ -0,0 +1,80 @@
+package bot
+
+import (
+	"carwash-bot/config"
+	"carwash-bot/internal/fsm"
+	"testing"
+	"time"
+)
+
+func TestParseInlineDay(t *testing.T) {
+	now := time.Date(2026, 3, 10, 15, 30, 0, 0, time.Local)
+	day := func(year int, month time.Month, d int) time.Time {
+		return time.Date(year, month, d, 0, 0, 0, 0, time.Local)
+	}
+
+	tests := []struct {
+		query  string
+		want   time.Time
+		wantOK bool
+	}{
+		{"", day(2026, 3, 10), true},
+		{"сегодня", day(2026, 3, 10), true},
+		{"  Завтра ", day(2026, 3, 11), true},
+		{"tomorrow", day(2026, 3, 11), true},
+		{"послезавтра", day(2026, 3, 12), true},
+		{"Day after tomorrow", day(2026, 3, 12), true},
+		{"15.03", day(2026, 3, 15), true},
+		{"10.03", day(2026, 3, 10), true},
+		{"09.03", day(2027, 3, 9), true},
+		{"01.01.2027", day(2027, 1, 1), true},
+		{"31.02", time.Time{}, false},
+		{"вчера", time.Time{}, false},
+		{"2026-03-15", time.Time{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			got, ok := parseInlineDay(tt.query, now)
+			if ok != tt.wantOK {
+				t.Fatalf("parseInlineDay(%q) ok = %v, ожидалось %v", tt.query, ok, tt.wantOK)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("parseInlineDay(%q) = %v, ожидалось %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+// Запись по ссылке на время и из ближайших слотов начинает сценарий с выбранным временем
+func TestSlotShortcutsStartFlow(t *testing.T) {
+	const userID = 42
+	cfg := &config.Config{AdminID: 1, StartTime: 8, EndTime: 20, SlotStepMinutes: 60, Bays: 1}
+	date := time.Now().AddDate(0, 0, 1)
+	dateStr := date.Format("02.01.2006")
+
+	tests := []struct {
+		name  string
+		start func(b *CarWashBot)
+	}{
+		{"ссылка на время", func(b *CarWashBot) {
+			b.startSlotBooking(userID, userID, date.Format("02012006")+"_1000")
+		}},
+		{"ближайшее время", func(b *CarWashBot) {
+			b.handleNearestSlotSelection(userID, userID, 0, dateStr+"_10:00")
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, _ := newTestBot(t, cfg)
+			tt.start(b)
+			if got := b.flow.State(userID); got == fsm.Idle {
+				t.Fatalf("сценарий не начат")
+			}
+			state := b.flow.Data(userID)
+			if state.SelectedDate != dateStr || state.SelectedTime != "10:00" {
+				t.Errorf("выбрано %s %s, ожидалось %s 10:00", state.SelectedDate, state.SelectedTime, dateStr)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package bot
+
+import (
+	"carwash-bot/internal/i18n"
+	"carwash-bot/internal/services"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// userLanguage - язык пользователя и был ли он выбран в настройках, а не взят из Telegram
+type userLanguage struct {
+	lang   i18n.Lang
+	chosen bool
+}
+
+// rememberLanguage определяет язык пользователя при каждом обновлении: выбранный
+// в настройках, иначе язык клиента Telegram
+func (b *CarWashBot) rememberLanguage(user *tgbotapi.User) {
+	if user == nil {
+		return
+	}
+
+	b.langLock.RLock()
+	cached, ok := b.languages[user.ID]
+	b.langLock.RUnlock()
+	if ok && cached.chosen {
+		return
+	}
+
+	lang := userLanguage{lang: i18n.Parse(user.LanguageCode)}
+	if !ok {
+		// Выбор из настроек читается из базы только при первом обращении
+		customer, err := b.storage.GetCustomer(user.ID)
+		if err != nil {
+			log.Printf("Ошибка получения профиля клиента: %v", err)
+		} else if customer != nil && i18n.Supported(i18n.Lang(customer.Language)) {
+			lang = userLanguage{lang: i18n.Lang(customer.Language), chosen: true}
+		}
+	}
+
+	b.langLock.Lock()
+	b.languages[user.ID] = lang
+	b.langLock.Unlock()
+}
+
+// lang возвращает язык пользователя. В личном чате chatID совпадает с userID,
+// поэтому функции, знающие только чат, тоже могут его использовать
+func (b *CarWashBot) lang(userID int64) i18n.Lang {
+	b.langLock.RLock()
+	defer b.langLock.RUnlock()
+	if l, ok := b.languages[userID]; ok {
+		return l.lang
+	}
+	return i18n.Default
+}
+
+// t переводит строку на язык пользователя
+func (b *CarWashBot) t(userID int64, key string, args ...any) string {
+	return i18n.T(b.lang(userID), key, args...)
+}
+
+// tn переводит строку с числом n на язык пользователя
+func (b *CarWashBot) tn(userID int64, key string, n int, args ...any) string {
+	return i18n.Plural(b.lang(userID), key, n, args...)
+}
+
+// errorText - причина отказа из services.PolicyError на языке пользователя.
+// Прочие ошибки возвращаются как есть
+func (b *CarWashBot) errorText(userID int64, err error) string {
+	var policyErr *services.PolicyError
+	if !errors.As(err, &policyErr) {
+		return err.Error()
+	}
+
+	lang := b.lang(userID)
+	args := make([]any, len(policyErr.Args))
+	for i, arg := range policyErr.Args {
+		switch v := arg.(type) {
+		case time.Duration:
+			args[i] = i18n.Duration(lang, v)
+		case time.Time:
+			args[i] = v.Format("15:04 02.01.2006")
+		default:
+			args[i] = v
+		}
+	}
+	if len(args) > 0 {
+		if n, ok := args[0].(int); ok {
+			return i18n.Plural(lang, policyErr.Reason, n, args...)
+		}
+	}
+	return i18n.T(lang, policyErr.Reason, args...)
+}
+
+// handleLanguageCommand предлагает выбрать язык интерфейса: /language
+func (b *CarWashBot) handleLanguageCommand(chatID, userID int64) {
+	var row []tgbotapi.InlineKeyboardButton
+	for _, lang := range i18n.Languages {
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(i18n.T(lang, "language.name"), "lang_"+string(lang)))
+	}
+
+	msg := tgbotapi.NewMessage(chatID, b.t(userID, "language.choose"))
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(row)
+	b.sendMessageWithSave(chatID, msg)
+}
+
+// handleLanguageCallback сохраняет выбранный язык: lang_<код>
+func (b *CarWashBot) handleLanguageCallback(chatID, userID int64, data string) {
+	lang := i18n.Lang(strings.TrimPrefix(data, "lang_"))
+	if !i18n.Supported(lang) {
+		return
+	}
+
+	if err := b.storage.SetCustomerLanguage(userID, string(lang)); err != nil {
+		log.Printf("Ошибка сохранения языка: %v", err)
+	}
+	b.langLock.Lock()
+	b.languages[userID] = userLanguage{lang: lang, chosen: true}
+	b.langLock.Unlock()
+
+	b.sendMessage(chatID, b.t(userID, "language.changed"))
+	b.sendWelcomeMessage(chatID)
+}
@@ -0,0 +1,212 @@
+package bot
+
+import (
+	"carwash-bot/internal/models"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Сколько последних операций показывать в балансе
+const loyaltyLedgerLimit = 10
+
+func (b *CarWashBot) loyaltyEnabled() bool {
+	return b.config.LoyaltyRewardPoints > 0
+}
+
+func (b *CarWashBot) loyaltyBalance(userID int64) int {
+	balance, err := b.storage.LoyaltyBalance(userID)
+	if err != nil {
+		log.Printf("Ошибка получения баланса баллов: %v", err)
+	}
+	return balance
+}
+
+// canRedeemReward проверяет, хватает ли клиенту баллов на бесплатную мойку
+func (b *CarWashBot) canRedeemReward(userID int64) bool {
+	return b.loyaltyEnabled() && b.loyaltyBalance(userID) >= b.config.LoyaltyRewardPoints
+}
+
+func (b *CarWashBot) addLoyaltyEntry(userID int64, bookingID string, delta int, reason, comment string, actorID int64) error {
+	err := b.storage.AddLoyaltyEntry(models.LoyaltyEntry{
+		UserID:    userID,
+		BookingID: bookingID,
+		Delta:     delta,
+		Reason:    reason,
+		Comment:   comment,
+		ActorID:   actorID,
+		Created:   time.Now(),
+	})
+	if err != nil {
+		log.Printf("Ошибка записи в журнал баллов: %v", err)
+	}
+	return err
+}
+
+// updateVisitPoints начисляет балл за мойку или забирает его, если администратор
+// исправил отметку на неявку. Бесплатные мойки баллов не приносят
+func (b *CarWashBot) updateVisitPoints(booking models.Booking, completed bool, actorID int64) {
+	if !b.loyaltyEnabled() || booking.LoyaltyPoints > 0 {
+		return
+	}
+	credited, err := b.storage.LoyaltyBookingDelta(booking.ID, models.LoyaltyVisit, models.LoyaltyVisitRevoked)
+	if err != nil {
+		log.Printf("Ошибка получения начислений по записи: %v", err)
+		return
+	}
+
+	switch {
+	case completed && credited == 0:
+		if b.addLoyaltyEntry(booking.UserID, booking.ID, 1, models.LoyaltyVisit, "", actorID) == nil {
+			b.notifyVisitPoints(booking.UserID)
+		}
+	case !completed && credited > 0:
+		b.addLoyaltyEntry(booking.UserID, booking.ID, -credited, models.LoyaltyVisitRevoked, "", actorID)
+	}
+}
+
+// notifyVisitPoints сообщает клиенту о начисленном балле и о том, сколько осталось до бесплатной мойки
+func (b *CarWashBot) notifyVisitPoints(userID int64) {
+	balance := b.loyaltyBalance(userID)
+	text := b.t(userID, "loyalty.earned", balance)
+	if left := b.config.LoyaltyRewardPoints - balance; left > 0 {
+		text += "\n" + b.tn(userID, "loyalty.left", left, left)
+	} else {
+		text += "\n" + b.t(userID, "loyalty.reward_ready")
+	}
+	b.sendMessage(userID, text)
+}
+
+// refundLoyaltyPoints возвращает баллы за отменённую бесплатную мойку
+func (b *CarWashBot) refundLoyaltyPoints(booking models.Booking, actorID int64) {
+	if booking.LoyaltyPoints == 0 {
+		return
+	}
+	spent, err := b.storage.LoyaltyBookingDelta(booking.ID, models.LoyaltyRedeem, models.LoyaltyRefund)
+	if err != nil {
+		log.Printf("Ошибка получения списаний по записи: %v", err)
+		return
+	}
+	if spent < 0 {
+		b.addLoyaltyEntry(booking.UserID, booking.ID, -spent, models.LoyaltyRefund, "", actorID)
+	}
+}
+
+// showBalance показывает баланс баллов и последние операции: ⭐ Мой баланс
+func (b *CarWashBot) showBalance(chatID, userID int64) {
+	if !b.loyaltyEnabled() {
+		b.sendMessage(chatID, b.t(userID, "loyalty.disabled"))
+		return
+	}
+
+	balance := b.loyaltyBalance(userID)
+	var sb strings.Builder
+	sb.WriteString(b.t(userID, "loyalty.balance", balance))
+	sb.WriteString("\n")
+	if left := b.config.LoyaltyRewardPoints - balance; left > 0 {
+		sb.WriteString(b.tn(userID, "loyalty.left", left, left))
+	} else {
+		sb.WriteString(b.t(userID, "loyalty.reward_ready"))
+	}
+	sb.WriteString("\n\n")
+	sb.WriteString(b.t(userID, "loyalty.rules", b.config.LoyaltyRewardPoints))
+
+	entries, err := b.storage.LoyaltyLedger(userID, loyaltyLedgerLimit)
+	if err != nil {
+		log.Printf("Ошибка получения журнала баллов: %v", err)
+	}
+	if len(entries) > 0 {
+		sb.WriteString("\n\n")
+		sb.WriteString(b.t(userID, "loyalty.history"))
+		for _, e := range entries {
+			sb.WriteString(fmt.Sprintf("\n%s  %+d  %s", e.Created.Format("02.01.2006"), e.Delta,
+				b.t(userID, "loyalty.reason."+e.Reason)))
+			if e.Comment != "" {
+				sb.WriteString(" — " + e.Comment)
+			}
+		}
+	}
+	b.sendMessage(chatID, sb.String())
+}
+
+// handlePointsCommand - баллы клиента для администратора:
+// /points <user_id> - баланс и журнал, /points <user_id> <+N|-N> [причина] - корректировка
+func (b *CarWashBot) handlePointsCommand(chatID, userID int64, text string) {
+	if !b.isAdmin(userID) {
+		b.sendMessage(chatID, "❌ Команда доступна только администратору")
+		return
+	}
+
+	usage := "Использование:\n/points <user_id> - баланс клиента\n" +
+		"/points <user_id> <+N|-N> [причина] - начислить или списать баллы"
+	args := strings.Fields(text)[1:]
+	if len(args) == 0 {
+		b.sendMessage(chatID, usage)
+		return
+	}
+	customerID, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		b.sendMessage(chatID, usage)
+		return
+	}
+
+	if len(args) == 1 {
+		b.showCustomerPoints(chatID, customerID)
+		return
+	}
+
+	delta, err := strconv.Atoi(args[1])
+	if err != nil || delta == 0 {
+		b.sendMessage(chatID, usage)
+		return
+	}
+	if balance := b.loyaltyBalance(customerID); balance+delta < 0 {
+		b.sendMessage(chatID, fmt.Sprintf("❌ Недостаточно баллов, баланс клиента: %d", balance))
+		return
+	}
+	comment := strings.Join(args[2:], " ")
+	if err := b.addLoyaltyEntry(customerID, "", delta, models.LoyaltyAdjust, comment, userID); err != nil {
+		b.sendMessage(chatID, "⚠️ Не удалось изменить баланс")
+		return
+	}
+
+	balance := b.loyaltyBalance(customerID)
+	b.sendMessage(chatID, fmt.Sprintf("✅ Баланс клиента %d: %d (%+d)", customerID, balance, delta))
+
+	notice := b.t(customerID, "loyalty.adjusted", delta, balance)
+	if comment != "" {
+		notice += "\n" + comment
+	}
+	b.sendMessage(customerID, notice)
+}
+
+// showCustomerPoints показывает администратору баланс и журнал баллов клиента
+func (b *CarWashBot) showCustomerPoints(chatID, customerID int64) {
+	entries, err := b.storage.LoyaltyLedger(customerID, loyaltyLedgerLimit)
+	if err != nil {
+		b.sendMessage(chatID, "⚠️ Ошибка при получении журнала баллов")
+		return
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("⭐ Клиент %d, баланс баллов: %d\n", customerID, b.loyaltyBalance(customerID)))
+	if len(entries) == 0 {
+		sb.WriteString("\nОпераций нет")
+	}
+	for _, e := range entries {
+		sb.WriteString(fmt.Sprintf("\n%s  %+d  %s", e.Created.Format("02.01.2006 15:04"), e.Delta,
+			b.t(chatID, "loyalty.reason."+e.Reason)))
+		if e.BookingID != "" {
+			sb.WriteString(" · " + e.BookingID)
+		}
+		if e.ActorID != 0 && e.ActorID != customerID {
+			sb.WriteString(fmt.Sprintf(" · админ %d", e.ActorID))
+		}
+		if e.Comment != "" {
+			sb.WriteString(" — " + e.Comment)
+		}
+	}
+	b.sendMessage(chatID, sb.String())
+}
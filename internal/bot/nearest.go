@@ -0,0 +1,117 @@
+package bot
+
+import (
+	"carwash-bot/internal/i18n"
+	"carwash-bot/internal/models"
+	"fmt"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// nearestSlot - свободное время для быстрой записи
+type nearestSlot struct {
+	Date  string
+	Time  string
+	Price int
+}
+
+// showNearestSlots предлагает ближайшие свободные времена для услуги по умолчанию,
+// чтобы записаться без перебора дней
+func (b *CarWashBot) showNearestSlots(chatID, userID int64) {
+	b.flow.Reset(userID)
+	if err := b.checkQuota(userID, "", ""); err != nil {
+		b.sendMessage(chatID, b.errorText(userID, err))
+		return
+	}
+
+	service := b.pricing.DefaultService()
+	slots := b.findNearestSlots(userID, service.Code, b.config.NearestSlotsCount)
+	if len(slots) == 0 {
+		days := b.userHorizonDays(userID)
+		b.sendMessage(chatID, b.tn(userID, "nearest.none", days, days))
+		return
+	}
+
+	// Короткие названия дней начинаются с понедельника
+	weekdayNames := i18n.WeekdaysShort(b.lang(userID))
+
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for _, slot := range slots {
+		date, _ := time.Parse("02.01.2006", slot.Date)
+		btnText := fmt.Sprintf("⚡ %s %s %s · %s",
+			weekdayNames[(date.Weekday()+6)%7], date.Format("02.01"), slot.Time, b.pricing.FormatPrice(slot.Price))
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(btnText, "asap_"+slot.Date+"_"+slot.Time),
+		))
+	}
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(b.t(userID, "nearest.other_day"), "back_to_dates"),
+		tgbotapi.NewInlineKeyboardButtonData(b.t(chatID, "menu.main"), "main_menu"),
+	))
+
+	text := b.t(userID, "nearest.title")
+	if len(b.pricing.Services) > 1 {
+		text = b.t(userID, "nearest.title_service", service.Name)
+	}
+
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(rows...)
+	b.sendMessageWithSave(chatID, msg)
+}
+
+// findNearestSlots ищет до count свободных времён, начиная с сегодняшнего дня и до горизонта записи.
+// Учитываются выходные дни, правила записи, занятость постов и мойщиков, лимит записей на день
+// и ограничение горизонта из-за неявок
+func (b *CarWashBot) findNearestSlots(userID int64, service string, count int) []nearestSlot {
+	if count <= 0 {
+		count = 1
+	}
+
+	var slots []nearestSlot
+	now := time.Now()
+	for day := 0; day < b.userHorizonDays(userID) && len(slots) < count; day++ {
+		date := now.AddDate(0, 0, day)
+		if b.isDayClosed(date) {
+			continue
+		}
+
+		dateStr := date.Format("02.01.2006")
+		if maxPerDay := b.quotaPolicy().MaxPerDay; !b.isAdmin(userID) && maxPerDay > 0 &&
+			b.quotaUsage(userID, dateStr, "").OnDay >= maxPerDay {
+			continue
+		}
+
+		open := b.openSlotTimes(dateStr, service)
+		for _, timeStr := range b.slotTimes() {
+			if len(slots) == count {
+				break
+			}
+			if !open[timeStr] {
+				continue
+			}
+			price, _ := b.slotPrice(dateStr, timeStr, service, "")
+			slots = append(slots, nearestSlot{Date: dateStr, Time: timeStr, Price: price})
+		}
+	}
+	return slots
+}
+
+// handleNearestSlotSelection записывает на выбранное ближайшее время: asap_<дата>_<время>
+func (b *CarWashBot) handleNearestSlotSelection(chatID, userID int64, messageID int, data string) {
+	parts := strings.SplitN(data, "_", 2)
+	if len(parts) != 2 {
+		b.sendMessage(chatID, b.t(userID, "error.format"))
+		return
+	}
+
+	b.flow.Reset(userID)
+	state := models.UserState{
+		SelectedDate:    parts[0],
+		SelectedService: b.pricing.DefaultService().Code,
+	}
+	if b.setState(chatID, userID, stateChoosingTime, state) {
+		b.handleTimeSelection(chatID, userID, messageID, parts[1])
+	}
+}
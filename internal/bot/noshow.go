@@ -0,0 +1,281 @@
+package bot
+
+import (
+	"carwash-bot/internal/models"
+	"carwash-bot/internal/services"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func (b *CarWashBot) noShowPolicy() services.NoShowPolicy {
+	return services.NoShowPolicy{
+		Limit:       b.config.NoShowLimit,
+		Penalty:     b.config.NoShowPenalty,
+		HorizonDays: b.config.NoShowHorizonDays,
+	}
+}
+
+func (b *CarWashBot) countNoShows(userID int64) int {
+	count, err := b.storage.CountNoShows(userID)
+	if err != nil {
+		log.Printf("Ошибка подсчёта неявок: %v", err)
+	}
+	return count
+}
+
+// checkNoShowPolicy применяет меры к клиенту с неявками. Пустая date проверяет только блокировку
+func (b *CarWashBot) checkNoShowPolicy(userID int64, date string) error {
+	var day time.Time
+	if date != "" {
+		parsed, err := time.Parse("02.01.2006", date)
+		if err != nil {
+			return &services.PolicyError{Reason: "policy.bad_date"}
+		}
+		day = parsed
+	}
+	return b.noShowPolicy().Check(time.Now(), day, b.countNoShows(userID))
+}
+
+// needsConfirmation сообщает, что запись клиента должен подтвердить администратор
+func (b *CarWashBot) needsConfirmation(userID int64) bool {
+	return !b.isAdmin(userID) && b.noShowPolicy().RequiresConfirmation(b.countNoShows(userID))
+}
+
+// userHorizonDays - на сколько дней вперёд пользователь может записаться с учётом неявок
+func (b *CarWashBot) userHorizonDays(userID int64) int {
+	days := b.bookingPolicy().MaxAdvanceDays
+	policy := b.noShowPolicy()
+	if !b.isAdmin(userID) && policy.Penalty == services.NoShowPenaltyRestrict &&
+		policy.Applies(b.countNoShows(userID)) && policy.HorizonDays < days {
+		days = policy.HorizonDays
+	}
+	return days
+}
+
+// handleAttendanceCallback обрабатывает отметки администратора:
+// admin_noshow:<id>, admin_done:<id>, admin_confirm:<id>, admin_reject:<id>
+func (b *CarWashBot) handleAttendanceCallback(query *tgbotapi.CallbackQuery) {
+	if !b.isAdmin(query.From.ID) {
+		b.answerCallback(query.ID, "❌ Действие доступно только администратору", true)
+		return
+	}
+
+	action, bookingID, _ := strings.Cut(query.Data, ":")
+	booking, err := b.storage.GetBookingByID(bookingID)
+	if err != nil || booking == nil {
+		b.answerCallback(query.ID, "⚠️ Запись не найдена", true)
+		return
+	}
+
+	switch action {
+	case "admin_noshow", "admin_done":
+		b.markAttendance(query, *booking, action == "admin_noshow")
+	case "admin_confirm":
+		b.confirmPendingBooking(query, *booking)
+	case "admin_reject":
+		b.rejectPendingBooking(query, *booking)
+	}
+}
+
+// markAttendance отмечает, приехал ли клиент. Отметку можно исправить повторным нажатием другой кнопки
+func (b *CarWashBot) markAttendance(query *tgbotapi.CallbackQuery, booking models.Booking, noShow bool) {
+	start, err := booking.StartsAt()
+	if err != nil || time.Now().Before(start) {
+		b.answerCallback(query.ID, "⏳ Отметить можно только после начала записи", true)
+		return
+	}
+
+	status, event, label := models.BookingCompleted, models.EventCompleted, "✅ ПРИЕХАЛ"
+	if noShow {
+		status, event, label = models.BookingNoShow, models.EventNoShow, "🚫 НЕ ПРИЕХАЛ"
+	}
+	if booking.Status == status {
+		b.answerCallback(query.ID, "ℹ️ Уже отмечено", false)
+		return
+	}
+
+	if err := b.storage.SetBookingStatus(booking.ID, status); err != nil {
+		b.answerCallback(query.ID, "⚠️ Не удалось сохранить отметку", true)
+		return
+	}
+	b.logBookingEvent(booking, event, booking.Date+" "+booking.Time, query.From.ID)
+	b.updateVisitPoints(booking, !noShow, query.From.ID)
+
+	if noShow {
+		noShows := b.countNoShows(booking.UserID)
+		b.answerCallback(query.ID, fmt.Sprintf("🚫 Неявка отмечена. Всего у клиента: %d", noShows), false)
+		b.notifyAboutNoShow(booking, noShows)
+	} else {
+		b.answerCallback(query.ID, "✅ Отмечено", false)
+		b.requestFeedbackSoon(booking)
+	}
+
+	// В канале помечаем пост, в личном чате админа обновляем обзор дня
+	if query.Message.Chat.ID == b.config.ChannelID {
+		edit := tgbotapi.NewEditMessageText(query.Message.Chat.ID, query.Message.MessageID,
+			fmt.Sprintf("%s\n%s", label, strings.TrimPrefix(strings.TrimPrefix(query.Message.Text, "🚫 НЕ ПРИЕХАЛ\n"), "✅ ПРИЕХАЛ\n")))
+		edit.ReplyMarkup = query.Message.ReplyMarkup
+		if _, err := b.botAPI.Send(edit); err != nil {
+			log.Printf("Ошибка обновления поста в канале: %v", err)
+		}
+		return
+	}
+	b.showAdminDay(query.Message.Chat.ID, query.Message.MessageID, booking.Date)
+}
+
+// notifyAboutNoShow сообщает клиенту о неявке и о мере, если она начала действовать
+func (b *CarWashBot) notifyAboutNoShow(booking models.Booking, noShows int) {
+	text := b.t(booking.UserID, "noshow.notice", booking.Date, booking.Time)
+
+	policy := b.noShowPolicy()
+	if policy.Limit > 0 && noShows == policy.Limit {
+		switch policy.Penalty {
+		case services.NoShowPenaltyConfirm:
+			text += b.t(booking.UserID, "noshow.penalty_confirm")
+		case services.NoShowPenaltyRestrict:
+			text += b.tn(booking.UserID, "noshow.penalty_restrict", policy.HorizonDays, policy.HorizonDays)
+		case services.NoShowPenaltyBlock:
+			text += b.t(booking.UserID, "noshow.penalty_block")
+		}
+	}
+	b.sendMessage(booking.UserID, text)
+}
+
+func (b *CarWashBot) confirmPendingBooking(query *tgbotapi.CallbackQuery, booking models.Booking) {
+	if booking.Status != models.BookingPending {
+		b.answerCallback(query.ID, "ℹ️ Запись уже не ждёт подтверждения", false)
+		return
+	}
+	if err := b.storage.SetBookingStatus(booking.ID, models.BookingActive); err != nil {
+		b.answerCallback(query.ID, "⚠️ Не удалось подтвердить запись", true)
+		return
+	}
+	b.logBookingEvent(booking, models.EventConfirmed, booking.Date+" "+booking.Time, query.From.ID)
+	b.answerCallback(query.ID, "✅ Запись подтверждена", false)
+
+	b.sendMessage(booking.UserID, b.t(booking.UserID, "booking.confirmed_by_admin",
+		booking.Date, booking.Time, booking.CarModel, booking.CarNumber))
+	b.editAdminNotice(query, "✅ ПОДТВЕРЖДЕНО")
+}
+
+func (b *CarWashBot) rejectPendingBooking(query *tgbotapi.CallbackQuery, booking models.Booking) {
+	if booking.Status != models.BookingPending {
+		b.answerCallback(query.ID, "ℹ️ Запись уже не ждёт подтверждения", false)
+		return
+	}
+	if err := b.storage.DeleteBooking(booking.ID); err != nil {
+		b.answerCallback(query.ID, "⚠️ Не удалось отклонить запись", true)
+		return
+	}
+	b.logBookingEvent(booking, models.EventCancelled, booking.Date+" "+booking.Time, query.From.ID)
+	b.refundLoyaltyPoints(booking, query.From.ID)
+	b.answerCallback(query.ID, "❌ Запись отклонена", false)
+
+	b.sendMessage(booking.UserID, b.t(booking.UserID, "booking.rejected_by_admin",
+		booking.Date, booking.Time, booking.CarModel, booking.CarNumber))
+	b.editAdminNotice(query, "❌ ОТКЛОНЕНО")
+	b.offerFreedSlot(booking.Date, booking.Time)
+}
+
+// editAdminNotice помечает уведомление админу о записи и убирает кнопки
+func (b *CarWashBot) editAdminNotice(query *tgbotapi.CallbackQuery, label string) {
+	edit := tgbotapi.NewEditMessageText(query.Message.Chat.ID, query.Message.MessageID,
+		fmt.Sprintf("%s\n%s", label, query.Message.Text))
+	if _, err := b.botAPI.Send(edit); err != nil {
+		log.Printf("Ошибка обновления сообщения: %v", err)
+	}
+}
+
+// handleDayCommand показывает админу записи на день с отметками явки: /day [дд.мм.гггг]
+func (b *CarWashBot) handleDayCommand(chatID, userID int64, text string) {
+	if !b.isAdmin(userID) {
+		b.sendMessage(chatID, "❌ Команда доступна только администратору")
+		return
+	}
+
+	dateStr := time.Now().Format("02.01.2006")
+	if args := strings.Fields(text)[1:]; len(args) > 0 {
+		if _, err := time.Parse("02.01.2006", args[0]); err != nil {
+			b.sendMessage(chatID, "❌ Неверный формат даты, нужно дд.мм.гггг")
+			return
+		}
+		dateStr = args[0]
+	}
+	b.showAdminDay(chatID, 0, dateStr)
+}
+
+// showAdminDay выводит обзор дня. Если messageID != 0, обновляет существующее сообщение
+func (b *CarWashBot) showAdminDay(chatID int64, messageID int, dateStr string) {
+	bookings, err := b.storage.GetBookingsByDate(dateStr)
+	if err != nil {
+		b.sendMessage(chatID, "⚠️ Ошибка при получении записей")
+		return
+	}
+	sort.Slice(bookings, func(i, j int) bool {
+		return bookings[i].Time < bookings[j].Time
+	})
+
+	statusIcons := map[string]string{
+		models.BookingActive:    "▫️",
+		models.BookingPending:   "⏳",
+		models.BookingNoShow:    "🚫",
+		models.BookingCompleted: "✅",
+
+		models.BookingAwaitingPayment: "💳",
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("📋 Записи на %s:\n\n", dateStr))
+	if len(bookings) == 0 {
+		sb.WriteString("Записей нет\n")
+	}
+
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for _, booking := range bookings {
+		sb.WriteString(fmt.Sprintf("%s %s · пост %d · %s %s", statusIcons[booking.Status], booking.Time,
+			booking.Bay, booking.CarModel, booking.CarNumber))
+		if noShows := b.countNoShows(booking.UserID); noShows > 0 {
+			sb.WriteString(fmt.Sprintf(" (неявок: %d)", noShows))
+		}
+		if phone := b.customerPhone(booking.UserID); phone != "" {
+			sb.WriteString(" 📞 " + phone)
+		}
+		sb.WriteString("\n")
+
+		if booking.Status == models.BookingPending || booking.Status == models.BookingAwaitingPayment {
+			continue
+		}
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🚫 "+booking.Time+" "+booking.CarNumber, "admin_noshow:"+booking.ID),
+			tgbotapi.NewInlineKeyboardButtonData("✅ "+booking.Time+" "+booking.CarNumber, "admin_done:"+booking.ID),
+		))
+	}
+	sb.WriteString("\n▫️ ожидается · ✅ приехал · 🚫 не приехал · ⏳ ждёт подтверждения · 💳 ждёт оплаты")
+
+	if date, err := time.Parse("02.01.2006", dateStr); err == nil {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("◀️ "+date.AddDate(0, 0, -1).Format("02.01"),
+				"admin_dayview:"+date.AddDate(0, 0, -1).Format("02.01.2006")),
+			tgbotapi.NewInlineKeyboardButtonData(date.AddDate(0, 0, 1).Format("02.01")+" ▶️",
+				"admin_dayview:"+date.AddDate(0, 0, 1).Format("02.01.2006")),
+		))
+	}
+	markup := tgbotapi.NewInlineKeyboardMarkup(rows...)
+
+	if messageID != 0 {
+		edit := tgbotapi.NewEditMessageTextAndMarkup(chatID, messageID, sb.String(), markup)
+		if _, err := b.botAPI.Send(edit); err != nil {
+			log.Printf("Ошибка обновления обзора дня: %v", err)
+		}
+		return
+	}
+
+	msg := tgbotapi.NewMessage(chatID, sb.String())
+	msg.ReplyMarkup = markup
+	b.sendMessageWithSave(chatID, msg)
+}
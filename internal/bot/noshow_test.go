@@ -0,0 +1,54 @@
+package bot
+
+import (
+	"carwash-bot/config"
+	"carwash-bot/internal/models"
+	"carwash-bot/internal/services"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Уведомление о неявке приходит на языке клиента вместе с мерой
+func TestNotifyAboutNoShowLanguage(t *testing.T) {
+	cfg := &config.Config{
+		AdminID:           1,
+		StartTime:         8,
+		EndTime:           20,
+		NoShowLimit:       2,
+		NoShowPenalty:     services.NoShowPenaltyRestrict,
+		NoShowHorizonDays: 3,
+	}
+	b, fake := newTestBot(t, cfg)
+	date := time.Now().AddDate(0, 0, -1).Format("02.01.2006")
+
+	tests := []struct {
+		name    string
+		userID  int64
+		code    string
+		noShows int
+		want    string
+	}{
+		{"русский без меры", 42, "ru", 1,
+			"🚫 Вы не приехали на мойку " + date + " в 10:00.\nЕсли планы меняются, пожалуйста, отменяйте запись заранее."},
+		{"английский с мерой", 43, "en", 2,
+			"🚫 You didn't show up for your car wash on " + date + " at 10:00.\nIf your plans change, please cancel your booking in advance." +
+				"\n\nFrom now on you can only book 3 days ahead."},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b.rememberLanguage(&tgbotapi.User{ID: tt.userID, LanguageCode: tt.code})
+			booking := addTestBooking(t, b, tt.userID, date, "10:00", models.BookingNoShow)
+			b.notifyAboutNoShow(booking, tt.noShows)
+
+			sent := fake.requests("sendMessage")
+			if len(sent) != i+1 {
+				t.Fatalf("sendMessage вызван %d раз, ожидалось %d", len(sent), i+1)
+			}
+			if got := sent[i].Get("text"); got != tt.want {
+				t.Errorf("text = %q, ожидалось %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,217 @@
+package bot
+
+import (
+	"carwash-bot/internal/models"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Режимы предоплаты (PREPAYMENT_MODE)
+const (
+	prepaymentOff  = "off"
+	prepaymentPeak = "peak"
+	prepaymentAll  = "all"
+)
+
+// Payload счёта: booking:<id записи>
+const paymentPayloadPrefix = "booking:"
+
+// needsPrepayment проверяет, нужно ли оплатить запись заранее.
+// Бесплатные записи и записи администраторов оплаты не требуют
+func (b *CarWashBot) needsPrepayment(userID int64, state models.UserState, price int) bool {
+	if b.config.PaymentProviderToken == "" || price <= 0 || b.isAdmin(userID) {
+		return false
+	}
+
+	switch b.config.PrepaymentMode {
+	case prepaymentAll:
+		return true
+	case prepaymentPeak:
+		start, err := models.SlotTime(state.SelectedDate, state.SelectedTime)
+		if err != nil {
+			return false
+		}
+		service, class := state.SelectedService, state.SelectedClass
+		if service == "" {
+			service = b.pricing.DefaultService().Code
+		}
+		if class == "" {
+			class = b.pricing.DefaultCarClass().Code
+		}
+		return b.pricing.IsPeak(service, class, start)
+	}
+	return false
+}
+
+// paymentAmount - сумма счёта в минимальных единицах валюты (копейках)
+func paymentAmount(booking models.Booking) int {
+	return booking.Price * 100
+}
+
+// requestPrepayment выставляет счёт за запись. Время удерживается за клиентом
+// PAYMENT_TIMEOUT_MINUTES, после этого неоплаченная запись снимается
+func (b *CarWashBot) requestPrepayment(chatID int64, booking models.Booking) {
+	userID := booking.UserID
+	timeout := time.Duration(b.config.PaymentTimeoutMinutes) * time.Minute
+
+	job := models.Job{
+		Kind:          models.JobPaymentExpiry,
+		BookingID:     booking.ID,
+		Slot:          booking.Date + " " + booking.Time,
+		OffsetMinutes: b.config.PaymentTimeoutMinutes,
+		RunAt:         time.Now().Add(timeout),
+	}
+	if err := b.storage.ScheduleJob(job); err != nil {
+		log.Printf("Ошибка планирования снятия неоплаченной записи %s: %v", booking.ID, err)
+	}
+
+	service := b.describeService(booking)
+	invoice := tgbotapi.NewInvoice(chatID,
+		b.t(userID, "payment.title"),
+		b.t(userID, "payment.description", booking.Date, booking.Time, booking.CarModel, booking.CarNumber),
+		paymentPayloadPrefix+booking.ID,
+		b.config.PaymentProviderToken,
+		"",
+		b.config.PaymentCurrency,
+		[]tgbotapi.LabeledPrice{{Label: service, Amount: paymentAmount(booking)}},
+	)
+	if _, err := b.botAPI.Send(invoice); err != nil {
+		log.Printf("Ошибка отправки счёта по записи %s: %v", booking.ID, err)
+		b.sendMessage(chatID, b.t(userID, "payment.invoice_error"))
+		return
+	}
+
+	reply := tgbotapi.NewMessage(chatID, b.t(userID, "payment.hold", b.config.PaymentTimeoutMinutes))
+	reply.ReplyMarkup = b.mainMenuKeyboard(userID)
+	if _, err := b.botAPI.Send(reply); err != nil {
+		log.Printf("Ошибка отправки сообщения: %v", err)
+	}
+}
+
+// unpaidBooking находит запись по payload счёта, если её ещё можно оплатить
+func (b *CarWashBot) unpaidBooking(payload string, userID int64) *models.Booking {
+	bookingID, ok := strings.CutPrefix(payload, paymentPayloadPrefix)
+	if !ok {
+		return nil
+	}
+	booking, err := b.storage.GetBookingByID(bookingID)
+	if err != nil {
+		log.Printf("Ошибка получения записи %s: %v", bookingID, err)
+		return nil
+	}
+	if booking == nil || booking.UserID != userID || booking.Status != models.BookingAwaitingPayment {
+		return nil
+	}
+	return booking
+}
+
+// handlePreCheckoutQuery подтверждает оплату, только если запись ещё ждёт её и сумма не изменилась
+func (b *CarWashBot) handlePreCheckoutQuery(query *tgbotapi.PreCheckoutQuery) {
+	answer := tgbotapi.PreCheckoutConfig{PreCheckoutQueryID: query.ID, OK: true}
+
+	booking := b.unpaidBooking(query.InvoicePayload, query.From.ID)
+	if booking == nil || query.TotalAmount != paymentAmount(*booking) || query.Currency != b.config.PaymentCurrency {
+		answer.OK = false
+		answer.ErrorMessage = b.t(query.From.ID, "payment.unavailable")
+	}
+
+	if _, err := b.botAPI.Request(answer); err != nil {
+		log.Printf("Ошибка ответа на проверку оплаты: %v", err)
+	}
+}
+
+// handleSuccessfulPayment сохраняет платёж и подтверждает запись
+func (b *CarWashBot) handleSuccessfulPayment(msg *tgbotapi.Message) {
+	chatID := msg.Chat.ID
+	userID := msg.From.ID
+	paid := msg.SuccessfulPayment
+
+	bookingID := strings.TrimPrefix(paid.InvoicePayload, paymentPayloadPrefix)
+	err := b.storage.AddPayment(models.Payment{
+		BookingID:        bookingID,
+		UserID:           userID,
+		Amount:           paid.TotalAmount,
+		Currency:         paid.Currency,
+		TelegramChargeID: paid.TelegramPaymentChargeID,
+		ProviderChargeID: paid.ProviderPaymentChargeID,
+		Created:          time.Now(),
+	})
+	if err != nil {
+		log.Printf("Ошибка сохранения платежа %s: %v", paid.TelegramPaymentChargeID, err)
+	}
+
+	booking := b.unpaidBooking(paid.InvoicePayload, userID)
+	if booking == nil {
+		// Запись сняли между проверкой и оплатой - деньги возвращает администратор
+		b.notifyAdmins(fmt.Sprintf("⚠️ Оплата по снятой записи %s\n💳 %.2f %s, платёж %s\n👤 ID: %d\nВерните деньги клиенту",
+			bookingID, float64(paid.TotalAmount)/100, paid.Currency, paid.TelegramPaymentChargeID, userID))
+		b.sendMessage(chatID, b.t(userID, "payment.late"))
+		return
+	}
+
+	if err := b.storage.SetBookingStatus(booking.ID, models.BookingActive); err != nil {
+		log.Printf("Ошибка подтверждения оплаченной записи %s: %v", booking.ID, err)
+	}
+	booking.Status = models.BookingActive
+	b.logBookingEvent(*booking, models.EventPaid,
+		fmt.Sprintf("%.2f %s, %s", float64(paid.TotalAmount)/100, paid.Currency, paid.TelegramPaymentChargeID), userID)
+
+	b.sendMessage(chatID, b.t(userID, "payment.received"))
+	b.finishBooking(chatID, *booking)
+}
+
+// bookingPayment возвращает предоплату по записи или nil, если запись не оплачивалась
+func (b *CarWashBot) bookingPayment(booking models.Booking) *models.Payment {
+	payment, err := b.storage.GetBookingPayment(booking.ID)
+	if err != nil {
+		log.Printf("Ошибка получения платежа по записи %s: %v", booking.ID, err)
+	}
+	return payment
+}
+
+// refundPrepayment записывает в историю, что предоплату за снятую запись нужно вернуть,
+// и сообщает об этом администраторам и клиенту. Возврат делается вручную у провайдера
+func (b *CarWashBot) refundPrepayment(booking models.Booking, actorID int64) {
+	payment := b.bookingPayment(booking)
+	if payment == nil {
+		return
+	}
+	amount := fmt.Sprintf("%.2f %s", float64(payment.Amount)/100, payment.Currency)
+	b.logBookingEvent(booking, models.EventRefundDue, amount+", "+payment.TelegramChargeID, actorID)
+
+	b.notifyAdmins(fmt.Sprintf("💳 Снята оплаченная запись %s %s\n🚗 %s %s\n👤 ID: %d\n"+
+		"Верните клиенту %s, платёж %s", booking.Date, booking.Time, booking.CarModel, booking.CarNumber,
+		booking.UserID, amount, payment.TelegramChargeID))
+	b.sendMessage(booking.UserID, b.t(booking.UserID, "payment.refund", booking.Date, booking.Time, amount))
+}
+
+// expireUnpaidBooking снимает запись, которую не оплатили вовремя, и освобождает время
+func (b *CarWashBot) expireUnpaidBooking(job models.Job) bool {
+	booking, err := b.storage.GetBookingByID(job.BookingID)
+	if err != nil {
+		log.Printf("Ошибка получения записи %s: %v", job.BookingID, err)
+		return false
+	}
+	if booking == nil || booking.Status != models.BookingAwaitingPayment {
+		return false
+	}
+	// Оплата могла прийти, но ещё не обработаться
+	if payment, err := b.storage.GetBookingPayment(booking.ID); err != nil || payment != nil {
+		return false
+	}
+
+	if err := b.storage.DeleteBooking(booking.ID); err != nil {
+		log.Printf("Ошибка снятия неоплаченной записи %s: %v", booking.ID, err)
+		return false
+	}
+	b.logBookingEvent(*booking, models.EventCancelled, booking.Date+" "+booking.Time+", не оплачена", 0)
+	b.refundLoyaltyPoints(*booking, 0)
+
+	b.sendMessage(booking.UserID, b.t(booking.UserID, "payment.expired", booking.Date, booking.Time))
+	b.offerFreedSlot(booking.Date, booking.Time)
+	return true
+}
@@ -0,0 +1,171 @@
+package bot
+
+import (
+	"carwash-bot/config"
+	"carwash-bot/internal/models"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func addUnpaidBooking(t *testing.T, b *CarWashBot, userID int64, date, timeStr string) models.Booking {
+	t.Helper()
+	return addTestBooking(t, b, userID, date, timeStr, models.BookingAwaitingPayment)
+}
+
+func prepaymentConfig() *config.Config {
+	return &config.Config{
+		AdminID:               1,
+		StartTime:             8,
+		EndTime:               20,
+		SlotStepMinutes:       60,
+		Bays:                  1,
+		PaymentProviderToken:  "provider-token",
+		PaymentCurrency:       "RUB",
+		PrepaymentMode:        prepaymentAll,
+		PaymentTimeoutMinutes: 0, // задача снятия сразу становится готовой к запуску
+	}
+}
+
+// Счёт -> проверка перед оплатой -> оплата -> снятие неоплаченной записи по задаче
+func TestPrepaymentFlow(t *testing.T) {
+	const userID = 42
+	b, fake := newTestBot(t, prepaymentConfig())
+	date := time.Now().AddDate(0, 0, 1).Format("02.01.2006")
+
+	paid := addUnpaidBooking(t, b, userID, date, "10:00")
+	b.requestPrepayment(userID, paid)
+
+	invoices := fake.requests("sendInvoice")
+	if len(invoices) != 1 {
+		t.Fatalf("sendInvoice вызван %d раз, ожидался 1", len(invoices))
+	}
+	invoice := invoices[0]
+	if got := invoice.Get("payload"); got != paymentPayloadPrefix+paid.ID {
+		t.Errorf("payload = %q, ожидался %q", got, paymentPayloadPrefix+paid.ID)
+	}
+	if got := invoice.Get("currency"); got != "RUB" {
+		t.Errorf("currency = %q, ожидалась RUB", got)
+	}
+	if !strings.Contains(invoice.Get("prices"), `"amount":150000`) {
+		t.Errorf("prices = %s, ожидалась сумма 150000", invoice.Get("prices"))
+	}
+
+	from := &tgbotapi.User{ID: userID}
+	tests := []struct {
+		name     string
+		payload  string
+		amount   int
+		currency string
+		wantOK   bool
+	}{
+		{"другая сумма", paymentPayloadPrefix + paid.ID, 100, "RUB", false},
+		{"другая валюта", paymentPayloadPrefix + paid.ID, 150000, "USD", false},
+		{"чужая запись", paymentPayloadPrefix + "missing", 150000, "RUB", false},
+		{"верный счёт", paymentPayloadPrefix + paid.ID, 150000, "RUB", true},
+	}
+	for i, tt := range tests {
+		b.handlePreCheckoutQuery(&tgbotapi.PreCheckoutQuery{
+			ID:             fmt.Sprintf("q%d", i),
+			From:           from,
+			Currency:       tt.currency,
+			TotalAmount:    tt.amount,
+			InvoicePayload: tt.payload,
+		})
+		answers := fake.requests("answerPreCheckoutQuery")
+		if len(answers) != i+1 {
+			t.Fatalf("%s: answerPreCheckoutQuery вызван %d раз, ожидалось %d", tt.name, len(answers), i+1)
+		}
+		if got := answers[i].Get("ok") == "true"; got != tt.wantOK {
+			t.Errorf("%s: ok = %v, ожидалось %v", tt.name, got, tt.wantOK)
+		}
+	}
+
+	b.handleSuccessfulPayment(&tgbotapi.Message{
+		From: from,
+		Chat: &tgbotapi.Chat{ID: userID},
+		SuccessfulPayment: &tgbotapi.SuccessfulPayment{
+			Currency:                "RUB",
+			TotalAmount:             150000,
+			InvoicePayload:          paymentPayloadPrefix + paid.ID,
+			TelegramPaymentChargeID: "tg-charge",
+			ProviderPaymentChargeID: "provider-charge",
+		},
+	})
+	booking, err := b.storage.GetBookingByID(paid.ID)
+	if err != nil || booking == nil {
+		t.Fatalf("оплаченная запись не найдена: %v", err)
+	}
+	if booking.Status != models.BookingActive {
+		t.Errorf("статус оплаченной записи = %q, ожидался %q", booking.Status, models.BookingActive)
+	}
+	if payment := b.bookingPayment(*booking); payment == nil || payment.Amount != 150000 {
+		t.Errorf("платёж не сохранён: %+v", payment)
+	}
+
+	unpaid := addUnpaidBooking(t, b, userID, date, "12:00")
+	b.requestPrepayment(userID, unpaid)
+	b.runDueJobs()
+
+	if booking, err := b.storage.GetBookingByID(paid.ID); err != nil || booking == nil {
+		t.Errorf("оплаченная запись снята по задаче: %v", err)
+	}
+	if booking, err := b.storage.GetBookingByID(unpaid.ID); err != nil || booking != nil {
+		t.Errorf("неоплаченная запись не снята: %+v, %v", booking, err)
+	}
+
+	expired := b.t(userID, "payment.expired", unpaid.Date, unpaid.Time)
+	found := false
+	for _, msg := range fake.requests("sendMessage") {
+		if msg.Get("text") == expired {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("клиенту не отправлено сообщение о снятии записи")
+	}
+}
+
+// Запись на то же время после снятия прежней получает свою задачу снятия
+// и не считается оплаченной чужим платежом
+func TestPrepaymentRebookSameSlot(t *testing.T) {
+	const userID = 42
+	b, _ := newTestBot(t, prepaymentConfig())
+	date := time.Now().AddDate(0, 0, 1).Format("02.01.2006")
+
+	expired := addUnpaidBooking(t, b, userID, date, "10:00")
+	b.requestPrepayment(userID, expired)
+	b.runDueJobs()
+	if booking, _ := b.storage.GetBookingByID(expired.ID); booking != nil {
+		t.Fatalf("первая неоплаченная запись не снята")
+	}
+
+	rebooked := addUnpaidBooking(t, b, userID, date, "10:00")
+	if rebooked.ID == expired.ID {
+		t.Fatalf("новая запись получила ID снятой: %s", rebooked.ID)
+	}
+	b.requestPrepayment(userID, rebooked)
+	b.runDueJobs()
+	if booking, _ := b.storage.GetBookingByID(rebooked.ID); booking != nil {
+		t.Errorf("повторная неоплаченная запись не снята: статус %q", booking.Status)
+	}
+
+	// Оплаченную запись снял администратор, клиент записался на то же время снова
+	paid := addUnpaidBooking(t, b, userID, date, "10:00")
+	err := b.storage.AddPayment(models.Payment{BookingID: paid.ID, UserID: userID, Amount: 150000,
+		Currency: "RUB", TelegramChargeID: "tg-charge", Created: time.Now()})
+	if err != nil {
+		t.Fatalf("AddPayment: %v", err)
+	}
+	if err := b.storage.DeleteBooking(paid.ID); err != nil {
+		t.Fatalf("DeleteBooking: %v", err)
+	}
+
+	again := addUnpaidBooking(t, b, userID, date, "10:00")
+	if payment := b.bookingPayment(again); payment != nil {
+		t.Errorf("новой записи достался платёж снятой: %+v", payment)
+	}
+}
@@ -0,0 +1,118 @@
+package bot
+
+import (
+	"carwash-bot/internal/i18n"
+	"carwash-bot/internal/models"
+	"log"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Режимы запроса телефона (PHONE_REQUIREMENT)
+const (
+	phoneOff      = "off"
+	phoneOptional = "optional"
+	phoneRequired = "required"
+)
+
+// customerPhone возвращает сохранённый телефон клиента или пустую строку
+func (b *CarWashBot) customerPhone(userID int64) string {
+	customer, err := b.storage.GetCustomer(userID)
+	if err != nil {
+		log.Printf("Ошибка получения профиля клиента: %v", err)
+		return ""
+	}
+	if customer == nil {
+		return ""
+	}
+	return customer.Phone
+}
+
+// needsPhone проверяет, нужно ли спросить телефон перед подтверждением записи
+func (b *CarWashBot) needsPhone(userID int64, state models.UserState) bool {
+	switch b.config.PhoneRequirement {
+	case phoneRequired:
+	case phoneOptional:
+		if state.PhoneSkipped {
+			return false
+		}
+	default:
+		return false
+	}
+	return b.customerPhone(userID) == ""
+}
+
+// askPhone просит поделиться контактом кнопкой Telegram
+func (b *CarWashBot) askPhone(chatID int64) {
+	sendButton := b.t(chatID, "phone.send")
+	text := b.t(chatID, "phone.ask", sendButton)
+	rows := [][]tgbotapi.KeyboardButton{
+		tgbotapi.NewKeyboardButtonRow(tgbotapi.NewKeyboardButtonContact(sendButton)),
+	}
+	if b.config.PhoneRequirement == phoneOptional {
+		text += " " + b.t(chatID, "phone.can_skip")
+		rows = append(rows, tgbotapi.NewKeyboardButtonRow(tgbotapi.NewKeyboardButton(b.t(chatID, "phone.skip"))))
+	}
+
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ReplyMarkup = tgbotapi.NewReplyKeyboard(rows...)
+	b.sendMessageWithSave(chatID, msg)
+}
+
+// handlePhoneInput обрабатывает текст на шаге телефона: пропуск или повторная подсказка
+func (b *CarWashBot) handlePhoneInput(chatID, userID int64, text string) {
+	if i18n.Matches(text, "phone.skip") && b.config.PhoneRequirement == phoneOptional {
+		state := b.flow.Data(userID)
+		state.PhoneSkipped = true
+		b.flow.SetData(userID, state)
+		b.continueAfterPhone(chatID, userID, b.t(userID, "phone.skipped"))
+		return
+	}
+	b.askPhone(chatID)
+}
+
+// handleContact сохраняет телефон из отправленного контакта. Принимается только
+// собственный контакт отправителя, а не пересланный чужой
+func (b *CarWashBot) handleContact(msg *tgbotapi.Message) {
+	chatID := msg.Chat.ID
+	userID := msg.From.ID
+
+	if msg.Contact.UserID != userID {
+		b.sendMessage(chatID, b.t(userID, "phone.not_yours", b.t(userID, "phone.send")))
+		return
+	}
+
+	phone := strings.TrimSpace(msg.Contact.PhoneNumber)
+	if !strings.HasPrefix(phone, "+") {
+		phone = "+" + phone
+	}
+	if err := b.storage.SetCustomerPhone(userID, phone); err != nil {
+		log.Printf("Ошибка сохранения телефона: %v", err)
+		b.sendMessage(chatID, b.t(userID, "phone.save_error"))
+		return
+	}
+
+	if b.flow.State(userID) == stateEnteringPhone {
+		b.continueAfterPhone(chatID, userID, b.t(userID, "phone.saved"))
+		return
+	}
+	reply := tgbotapi.NewMessage(chatID, b.t(userID, "phone.saved"))
+	reply.ReplyMarkup = b.mainMenuKeyboard(userID)
+	if _, err := b.botAPI.Send(reply); err != nil {
+		log.Printf("Ошибка отправки сообщения: %v", err)
+	}
+}
+
+// continueAfterPhone возвращает главное меню вместо кнопки контакта и показывает итог записи
+func (b *CarWashBot) continueAfterPhone(chatID, userID int64, note string) {
+	reply := tgbotapi.NewMessage(chatID, note)
+	reply.ReplyMarkup = b.mainMenuKeyboard(userID)
+	if _, err := b.botAPI.Send(reply); err != nil {
+		log.Printf("Ошибка отправки сообщения: %v", err)
+	}
+
+	if b.setState(chatID, userID, stateConfirming, b.flow.Data(userID)) {
+		b.showBookingSummary(chatID, userID, 0)
+	}
+}
@@ -0,0 +1,143 @@
+package bot
+
+import (
+	"carwash-bot/internal/models"
+	"carwash-bot/internal/services"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Ключи настроек, которыми админ может переопределить правила из конфигурации
+const (
+	settingMinNotice    = "policy.min_notice_minutes"
+	settingMaxAdvance   = "policy.max_advance_days"
+	settingChangeCutoff = "policy.change_cutoff_minutes"
+)
+
+// loadPolicy собирает правила из конфигурации и сохранённых админом настроек
+func (b *CarWashBot) loadPolicy() {
+	minNotice := b.config.MinBookingNoticeMinutes
+	maxAdvance := b.config.BookingHorizonDays
+	cutoff := b.config.ChangeCutoffMinutes
+
+	for key, target := range map[string]*int{
+		settingMinNotice:    &minNotice,
+		settingMaxAdvance:   &maxAdvance,
+		settingChangeCutoff: &cutoff,
+	} {
+		value, err := b.storage.GetSetting(key)
+		if err != nil {
+			log.Printf("Ошибка чтения настройки %s: %v", key, err)
+			continue
+		}
+		if num, err := strconv.Atoi(value); err == nil {
+			*target = num
+		}
+	}
+
+	b.policyLock.Lock()
+	b.policy = services.BookingPolicy{
+		MinNotice:      time.Duration(minNotice) * time.Minute,
+		MaxAdvanceDays: maxAdvance,
+		ChangeCutoff:   time.Duration(cutoff) * time.Minute,
+	}
+	b.policyLock.Unlock()
+}
+
+func (b *CarWashBot) bookingPolicy() services.BookingPolicy {
+	b.policyLock.RLock()
+	defer b.policyLock.RUnlock()
+	return b.policy
+}
+
+// checkBookingPolicy проверяет правила записи. Админы могут записывать в обход правил,
+// но только в рабочее время
+func (b *CarWashBot) checkBookingPolicy(userID int64, date, timeStr string) error {
+	// Нерабочие дни и время вне сетки недоступны и администраторам
+	if !b.isSlotOnGrid(date, timeStr) {
+		return &services.PolicyError{Reason: "policy.closed_time"}
+	}
+	if b.isAdmin(userID) {
+		return nil
+	}
+
+	start, err := models.SlotTime(date, timeStr)
+	if err != nil {
+		return &services.PolicyError{Reason: "policy.bad_date"}
+	}
+	return b.bookingPolicy().CheckBooking(time.Now(), start)
+}
+
+// checkChangePolicy проверяет, можно ли отменить или перенести запись
+func (b *CarWashBot) checkChangePolicy(userID int64, booking models.Booking) error {
+	if b.isAdmin(userID) {
+		return nil
+	}
+
+	start, err := booking.StartsAt()
+	if err != nil {
+		return &services.PolicyError{Reason: "policy.bad_date"}
+	}
+	return b.bookingPolicy().CheckChange(time.Now(), start)
+}
+
+// handlePolicyCommand показывает и меняет правила записи:
+// /policy, /policy notice 30, /policy advance 45, /policy cutoff 120
+func (b *CarWashBot) handlePolicyCommand(chatID, userID int64, text string) {
+	if !b.isAdmin(userID) {
+		b.sendMessage(chatID, "❌ Команда доступна только администратору")
+		return
+	}
+
+	args := strings.Fields(text)[1:]
+	if len(args) == 0 {
+		b.sendMessage(chatID, b.formatPolicy())
+		return
+	}
+
+	value, err := strconv.Atoi(args[len(args)-1])
+	if len(args) != 2 || err != nil || value < 0 {
+		b.sendMessage(chatID, "Использование:\n/policy notice <минуты>\n/policy advance <дни>\n/policy cutoff <минуты>")
+		return
+	}
+
+	var key string
+	switch args[0] {
+	case "notice":
+		key = settingMinNotice
+	case "advance":
+		if value == 0 {
+			b.sendMessage(chatID, "❌ Горизонт записи должен быть не меньше 1 дня")
+			return
+		}
+		key = settingMaxAdvance
+	case "cutoff":
+		key = settingChangeCutoff
+	default:
+		b.sendMessage(chatID, "❌ Неизвестное правило. Доступны: notice, advance, cutoff")
+		return
+	}
+
+	if err := b.storage.SetSetting(key, strconv.Itoa(value)); err != nil {
+		b.sendMessage(chatID, "⚠️ Не удалось сохранить настройку")
+		return
+	}
+	b.loadPolicy()
+	b.sendMessage(chatID, "✅ Правила обновлены\n\n"+b.formatPolicy())
+}
+
+func (b *CarWashBot) formatPolicy() string {
+	policy := b.bookingPolicy()
+	return fmt.Sprintf(`📋 Правила записи:
+⏱ Минимальное время до записи: %s
+📅 Запись вперёд: %d дн.
+🚫 Отмена и перенос: не позднее чем за %s
+
+Администраторы не ограничены этими правилами.`,
+		services.FormatDuration(policy.MinNotice),
+		policy.MaxAdvanceDays,
+		services.FormatDuration(policy.ChangeCutoff))
+}
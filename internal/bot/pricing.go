@@ -0,0 +1,167 @@
+package bot
+
+import (
+	"carwash-bot/internal/models"
+	"fmt"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// startBooking начинает новую запись: проверяет лимиты и предлагает выбрать услугу
+func (b *CarWashBot) startBooking(chatID, userID int64) {
+	b.flow.Reset(userID)
+	if err := b.checkQuota(userID, "", ""); err != nil {
+		b.sendMessage(chatID, b.errorText(userID, err))
+		return
+	}
+
+	// Если услуга одна, выбирать нечего
+	if len(b.pricing.Services) == 1 {
+		if b.setState(chatID, userID, stateChoosingDay, models.UserState{SelectedService: b.pricing.DefaultService().Code}) {
+			b.showDaySelection(chatID)
+		}
+		return
+	}
+	if !b.setState(chatID, userID, stateChoosingService, models.UserState{}) {
+		return
+	}
+
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for _, service := range b.pricing.Services {
+		btnText := b.t(userID, "service.from", service.Name, b.pricing.FormatPrice(b.minServicePrice(service.Code)))
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(btnText, "svc_"+service.Code),
+		))
+	}
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(b.t(userID, "menu.main"), "main_menu"),
+	))
+
+	msg := tgbotapi.NewMessage(chatID, b.t(userID, "service.choose"))
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(rows...)
+	b.sendMessageWithSave(chatID, msg)
+}
+
+func (b *CarWashBot) handleServiceSelection(chatID, userID int64, messageID int, code string) {
+	if _, ok := b.pricing.Service(code); !ok {
+		b.sendMessage(chatID, b.t(userID, "service.not_found"))
+		b.startBooking(chatID, userID)
+		return
+	}
+
+	if b.setState(chatID, userID, stateChoosingDay, models.UserState{SelectedService: code}) {
+		b.showCalendar(chatID, messageID, time.Now())
+	}
+}
+
+// askCarClassOrInfo после выбора времени предлагает выбрать класс авто,
+// а если класс один - сразу просит ввести данные машины
+func (b *CarWashBot) askCarClassOrInfo(chatID, userID int64, messageID int) {
+	state := b.flow.Data(userID)
+	if state.SelectedService == "" {
+		state.SelectedService = b.pricing.DefaultService().Code
+	}
+
+	if len(b.pricing.CarClasses) == 1 {
+		state.SelectedClass = b.pricing.DefaultCarClass().Code
+		b.flow.SetData(userID, state)
+		b.askCarInfo(chatID, userID, messageID)
+		return
+	}
+	if !b.setState(chatID, userID, stateChoosingClass, state) {
+		return
+	}
+
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for _, class := range b.pricing.CarClasses {
+		btnText := class.Name
+		if price, err := b.slotPrice(state.SelectedDate, state.SelectedTime, state.SelectedService, class.Code); err == nil {
+			btnText = fmt.Sprintf("%s — %s", class.Name, b.pricing.FormatPrice(price))
+		}
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(btnText, "cls_"+class.Code),
+		))
+	}
+
+	msg := tgbotapi.NewMessage(chatID, b.t(userID, "class.choose"))
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(rows...)
+	b.showScreen(chatID, messageID, msg)
+}
+
+func (b *CarWashBot) handleCarClassSelection(chatID, userID int64, messageID int, code string) {
+	state := b.flow.Data(userID)
+	if state.SelectedDate == "" || state.SelectedTime == "" {
+		b.sendMessage(chatID, b.t(userID, "flow.stale"))
+		return
+	}
+	if _, ok := b.pricing.CarClass(code); !ok {
+		b.sendMessage(chatID, b.t(userID, "class.not_found"))
+		return
+	}
+
+	state.SelectedClass = code
+	b.flow.SetData(userID, state)
+	b.askCarInfo(chatID, userID, messageID)
+}
+
+// askCarInfo переводит пользователя к вводу марки и номера машины
+func (b *CarWashBot) askCarInfo(chatID, userID int64, messageID int) {
+	if !b.setState(chatID, userID, stateEnteringCarInfo, b.flow.Data(userID)) {
+		return
+	}
+
+	msg := tgbotapi.NewMessage(chatID, b.t(userID, "car.prompt")+"\n\n"+b.t(userID, "car.example"))
+	b.showScreen(chatID, messageID, msg)
+}
+
+// slotPrice рассчитывает цену услуги на указанное время
+func (b *CarWashBot) slotPrice(date, timeStr, service, class string) (int, error) {
+	start, err := models.SlotTime(date, timeStr)
+	if err != nil {
+		return 0, err
+	}
+	if service == "" {
+		service = b.pricing.DefaultService().Code
+	}
+	if class == "" {
+		class = b.pricing.DefaultCarClass().Code
+	}
+	return b.pricing.Price(service, class, start)
+}
+
+// minServicePrice - минимальная цена услуги для легкового авто в ближайшую неделю,
+// показывается в выборе услуги как "от ..."
+func (b *CarWashBot) minServicePrice(service string) int {
+	class := b.pricing.DefaultCarClass().Code
+	min := -1
+	now := time.Now()
+	for day := 0; day < 7; day++ {
+		for _, timeStr := range b.slotTimes() {
+			start, err := models.SlotTime(now.AddDate(0, 0, day).Format("02.01.2006"), timeStr)
+			if err != nil {
+				continue
+			}
+			if price, err := b.pricing.Price(service, class, start); err == nil && (min < 0 || price < min) {
+				min = price
+			}
+		}
+	}
+	if min < 0 {
+		return 0
+	}
+	return min
+}
+
+// describeService возвращает описание услуги записи: "Комплексная мойка, Кроссовер"
+func (b *CarWashBot) describeService(booking models.Booking) string {
+	var parts []string
+	if service, ok := b.pricing.Service(booking.Service); ok {
+		parts = append(parts, service.Name)
+	}
+	if class, ok := b.pricing.CarClass(booking.CarClass); ok && len(b.pricing.CarClasses) > 1 {
+		parts = append(parts, class.Name)
+	}
+	return strings.Join(parts, ", ")
+}
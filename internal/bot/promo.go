@@ -0,0 +1,369 @@
+package bot
+
+import (
+	"carwash-bot/internal/models"
+	"carwash-bot/internal/services"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Префикс промокода в ссылке: t.me/<бот>?start=promo_WINTER20
+const promoStartPrefix = "promo_"
+
+// Ответ на шаге ввода промокода, чтобы продолжить без него
+const promoSkipInput = "-"
+
+// promoError - причина, по которой промокод не подошёл, на языке пользователя
+func (b *CarWashBot) promoError(userID int64, err error) string {
+	var policyErr *services.PolicyError
+	if errors.As(err, &policyErr) {
+		return b.errorText(userID, err)
+	}
+	log.Printf("Ошибка проверки промокода: %v", err)
+	return b.t(userID, "promo.error")
+}
+
+// findPromo находит промокод и проверяет, что клиент может применить его к мойке start.
+// Нулевой start проверяет промокод без привязки к записи
+func (b *CarWashBot) findPromo(userID int64, code string, start time.Time, service string) (*models.PromoCode, error) {
+	promo, err := b.storage.GetPromoCode(code)
+	if err != nil {
+		return nil, err
+	}
+	if promo == nil {
+		return nil, services.ErrPromoNotFound
+	}
+	total, byUser, err := b.storage.PromoUsage(code, userID)
+	if err != nil {
+		return nil, err
+	}
+	usage := services.PromoUsage{Total: total, ByUser: byUser}
+	if err := services.CheckPromo(*promo, time.Now(), start, service, usage); err != nil {
+		return nil, err
+	}
+	return promo, nil
+}
+
+// promoDiscount - скидка по промокоду из сценария записи. Бесплатная мойка за баллы скидку исключает
+func (b *CarWashBot) promoDiscount(userID int64, state models.UserState, price int) (int, error) {
+	if state.PromoCode == "" || state.UseReward {
+		return 0, nil
+	}
+	start, err := models.SlotTime(state.SelectedDate, state.SelectedTime)
+	if err != nil {
+		return 0, err
+	}
+	service := state.SelectedService
+	if service == "" {
+		service = b.pricing.DefaultService().Code
+	}
+	promo, err := b.findPromo(userID, state.PromoCode, start, service)
+	if err != nil {
+		return 0, err
+	}
+	return services.PromoDiscount(*promo, price), nil
+}
+
+// pendingPromo - промокод, который клиент получил по ссылке и ещё не использовал
+func (b *CarWashBot) pendingPromo(userID int64) string {
+	customer, err := b.storage.GetCustomer(userID)
+	if err != nil {
+		log.Printf("Ошибка получения профиля клиента: %v", err)
+		return ""
+	}
+	if customer == nil {
+		return ""
+	}
+	return customer.PromoCode
+}
+
+// askPromoCode просит ввести промокод вместо итога записи
+func (b *CarWashBot) askPromoCode(chatID, userID int64, messageID int) {
+	if !b.setState(chatID, userID, stateEnteringPromo, b.flow.Data(userID)) {
+		return
+	}
+	b.showScreen(chatID, messageID, tgbotapi.NewMessage(chatID, b.t(userID, "promo.prompt", promoSkipInput)))
+}
+
+// handlePromoInput проверяет введённый промокод и возвращает к итогу записи
+func (b *CarWashBot) handlePromoInput(chatID, userID int64, text string) {
+	state := b.flow.Data(userID)
+
+	if strings.TrimSpace(text) == promoSkipInput {
+		state.PromoCode = ""
+	} else {
+		state.PromoCode = services.NormalizePromoCode(text)
+		if _, err := b.promoDiscount(userID, state, 0); err != nil {
+			b.sendMessage(chatID, b.promoError(userID, err)+"\n\n"+b.t(userID, "promo.prompt", promoSkipInput))
+			return
+		}
+	}
+
+	if b.setState(chatID, userID, stateConfirming, state) {
+		b.showBookingSummary(chatID, userID, 0)
+	}
+}
+
+// handleStartPayload обрабатывает параметр ссылки /start <payload>
+func (b *CarWashBot) handleStartPayload(chatID, userID int64, payload string) {
+	if slot, ok := strings.CutPrefix(payload, slotStartPrefix); ok {
+		b.startSlotBooking(chatID, userID, slot)
+		return
+	}
+	if code, ok := strings.CutPrefix(payload, promoStartPrefix); ok {
+		b.rememberPromo(chatID, userID, services.NormalizePromoCode(code))
+	}
+	b.sendWelcomeMessage(chatID)
+}
+
+// rememberPromo сохраняет промокод из ссылки, чтобы подставить его в следующую запись
+func (b *CarWashBot) rememberPromo(chatID, userID int64, code string) {
+	promo, err := b.findPromo(userID, code, time.Time{}, "")
+	if err != nil {
+		b.sendMessage(chatID, b.promoError(userID, err))
+		return
+	}
+	if err := b.storage.SetCustomerPromo(userID, promo.Code); err != nil {
+		log.Printf("Ошибка сохранения промокода: %v", err)
+		b.sendMessage(chatID, b.t(userID, "promo.error"))
+		return
+	}
+	b.sendMessage(chatID, b.t(userID, "promo.saved", promo.Code, b.describeDiscount(*promo)))
+}
+
+// describeDiscount - размер скидки: "20%" или "300 ₽"
+func (b *CarWashBot) describeDiscount(promo models.PromoCode) string {
+	if promo.Percent > 0 {
+		return fmt.Sprintf("%d%%", promo.Percent)
+	}
+	return b.pricing.FormatPrice(promo.Amount)
+}
+
+// rescheduleDiscount пересчитывает скидку для нового времени записи.
+// Если в новое время промокод не действует, скидка снимается
+func (b *CarWashBot) rescheduleDiscount(booking models.Booking, newDate, newTime string, price int) (string, int) {
+	if booking.PromoCode == "" || price == 0 {
+		return "", 0
+	}
+	promo, err := b.storage.GetPromoCode(booking.PromoCode)
+	if err != nil || promo == nil {
+		return "", 0
+	}
+	start, err := models.SlotTime(newDate, newTime)
+	if err != nil {
+		return "", 0
+	}
+	// Запись уже учтена в использовании промокода, поэтому лимиты не проверяются
+	if services.CheckPromo(*promo, time.Now(), start, booking.Service, services.PromoUsage{}) != nil {
+		return "", 0
+	}
+	return promo.Code, services.PromoDiscount(*promo, price)
+}
+
+// handlePromoCommand управляет промокодами:
+// /promo, /promo add <код> <20%|300> [параметры], /promo off <код>, /promo on <код>
+func (b *CarWashBot) handlePromoCommand(chatID, userID int64, text string) {
+	if !b.isAdmin(userID) {
+		b.sendMessage(chatID, "❌ Команда доступна только администратору")
+		return
+	}
+
+	args := strings.Fields(text)[1:]
+	if len(args) == 0 {
+		b.showPromoCodes(chatID)
+		return
+	}
+
+	usage := "Использование:\n" +
+		"/promo add <код> <20%|300> [from=дд.мм.гггг] [until=дд.мм.гггг] [limit=N] [per_user=N] " +
+		"[services=код,код] [time=10:00-16:00]\n" +
+		"/promo off <код> - отключить\n/promo on <код> - включить"
+
+	switch args[0] {
+	case "add":
+		if len(args) < 3 {
+			b.sendMessage(chatID, usage)
+			return
+		}
+		promo, err := b.parsePromo(args[1], args[2], args[3:])
+		if err != nil {
+			b.sendMessage(chatID, "❌ "+err.Error()+"\n\n"+usage)
+			return
+		}
+		if existing, err := b.storage.GetPromoCode(promo.Code); err == nil && existing != nil {
+			b.sendMessage(chatID, "❌ Такой промокод уже есть")
+			return
+		}
+		if err := b.storage.AddPromoCode(promo); err != nil {
+			log.Printf("Ошибка добавления промокода: %v", err)
+			b.sendMessage(chatID, "⚠️ Не удалось добавить промокод")
+			return
+		}
+		b.sendMessage(chatID, fmt.Sprintf("✅ Промокод добавлен\n\n%s\n\nСсылка: %s",
+			b.describePromo(promo), b.promoLink(promo.Code)))
+
+	case "off", "on":
+		if len(args) < 2 {
+			b.sendMessage(chatID, usage)
+			return
+		}
+		code := services.NormalizePromoCode(args[1])
+		if err := b.storage.SetPromoActive(code, args[0] == "on"); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				b.sendMessage(chatID, "❌ Промокод не найден")
+				return
+			}
+			b.sendMessage(chatID, "⚠️ Не удалось изменить промокод")
+			return
+		}
+		if args[0] == "on" {
+			b.sendMessage(chatID, fmt.Sprintf("✅ Промокод %s включён", code))
+		} else {
+			b.sendMessage(chatID, fmt.Sprintf("✅ Промокод %s отключён", code))
+		}
+
+	default:
+		b.sendMessage(chatID, usage)
+	}
+}
+
+// parsePromo разбирает аргументы /promo add
+func (b *CarWashBot) parsePromo(codeArg, discountArg string, options []string) (models.PromoCode, error) {
+	promo := models.PromoCode{
+		Code:    services.NormalizePromoCode(codeArg),
+		Active:  true,
+		Created: time.Now(),
+	}
+	if !services.ValidPromoCode(promo.Code) {
+		return promo, errors.New("код - от 3 до 32 латинских букв, цифр, _ или -")
+	}
+
+	if percent, ok := strings.CutSuffix(discountArg, "%"); ok {
+		n, err := strconv.Atoi(percent)
+		if err != nil || n <= 0 || n > 100 {
+			return promo, errors.New("скидка в процентах - от 1 до 100")
+		}
+		promo.Percent = n
+	} else {
+		n, err := strconv.Atoi(discountArg)
+		if err != nil || n <= 0 {
+			return promo, errors.New("неверный размер скидки")
+		}
+		promo.Amount = n
+	}
+
+	for _, option := range options {
+		key, value, ok := strings.Cut(option, "=")
+		if !ok {
+			return promo, fmt.Errorf("неизвестный параметр %s", option)
+		}
+		switch key {
+		case "from", "until":
+			if _, err := time.Parse(models.DateFormat, value); err != nil {
+				return promo, fmt.Errorf("неверная дата %s, нужно дд.мм.гггг", value)
+			}
+			if key == "from" {
+				promo.ValidFrom = value
+			} else {
+				promo.ValidUntil = value
+			}
+		case "limit", "per_user":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 {
+				return promo, fmt.Errorf("неверное число %s", value)
+			}
+			if key == "limit" {
+				promo.MaxUses = n
+			} else {
+				promo.MaxPerUser = n
+			}
+		case "services":
+			for _, code := range strings.Split(value, ",") {
+				if _, ok := b.pricing.Service(code); !ok {
+					return promo, fmt.Errorf("услуга %s не найдена", code)
+				}
+				promo.Services = append(promo.Services, code)
+			}
+		case "time":
+			from, to, ok := strings.Cut(value, "-")
+			_, errFrom := time.Parse(models.TimeFormat, from)
+			_, errTo := time.Parse(models.TimeFormat, to)
+			if !ok || errFrom != nil || errTo != nil || from >= to {
+				return promo, fmt.Errorf("неверное время %s, нужно 10:00-16:00", value)
+			}
+			promo.TimeFrom, promo.TimeTo = from, to
+		default:
+			return promo, fmt.Errorf("неизвестный параметр %s", key)
+		}
+	}
+	return promo, nil
+}
+
+func (b *CarWashBot) showPromoCodes(chatID int64) {
+	promos, err := b.storage.ListPromoCodes()
+	if err != nil {
+		b.sendMessage(chatID, "⚠️ Ошибка при получении промокодов")
+		return
+	}
+	if len(promos) == 0 {
+		b.sendMessage(chatID, "🏷 Промокодов нет.\n\nДобавить: /promo add WINTER20 20%")
+		return
+	}
+
+	var sb strings.Builder
+	sb.WriteString("🏷 Промокоды:\n")
+	for _, promo := range promos {
+		total, _, err := b.storage.PromoUsage(promo.Code, 0)
+		if err != nil {
+			log.Printf("Ошибка подсчёта использований промокода: %v", err)
+		}
+		sb.WriteString("\n" + b.describePromo(promo))
+		if promo.MaxUses > 0 {
+			sb.WriteString(fmt.Sprintf("\nИспользован: %d из %d", total, promo.MaxUses))
+		} else {
+			sb.WriteString(fmt.Sprintf("\nИспользован: %d", total))
+		}
+		sb.WriteString("\n" + b.promoLink(promo.Code) + "\n")
+	}
+	b.sendMessage(chatID, sb.String())
+}
+
+// describePromo - описание промокода и его ограничений для администратора
+func (b *CarWashBot) describePromo(promo models.PromoCode) string {
+	status := "✅"
+	if !promo.Active {
+		status = "⛔"
+	}
+	text := fmt.Sprintf("%s %s — скидка %s", status, promo.Code, b.describeDiscount(promo))
+	if promo.ValidFrom != "" || promo.ValidUntil != "" {
+		text += fmt.Sprintf("\nДаты мойки: %s — %s", orDash(promo.ValidFrom), orDash(promo.ValidUntil))
+	}
+	if promo.TimeFrom != "" {
+		text += fmt.Sprintf("\nВремя: %s-%s", promo.TimeFrom, promo.TimeTo)
+	}
+	if len(promo.Services) > 0 {
+		text += "\nУслуги: " + strings.Join(promo.Services, ", ")
+	}
+	if promo.MaxPerUser > 0 {
+		text += fmt.Sprintf("\nНа клиента: %d", promo.MaxPerUser)
+	}
+	return text
+}
+
+func (b *CarWashBot) promoLink(code string) string {
+	return fmt.Sprintf("https://t.me/%s?start=%s%s", b.botAPI.Self.UserName, promoStartPrefix, code)
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "…"
+	}
+	return s
+}
@@ -0,0 +1,139 @@
+package bot
+
+import (
+	"carwash-bot/internal/models"
+	"carwash-bot/internal/services"
+	"log"
+	"time"
+)
+
+func (b *CarWashBot) quotaPolicy() services.QuotaPolicy {
+	return services.QuotaPolicy{
+		MaxActive:      b.config.MaxActiveBookings,
+		MaxPerDay:      b.config.MaxBookingsPerDay,
+		MaxPerPlate:    b.config.MaxBookingsPerPlate,
+		CancelLimit:    b.config.CancelLimit,
+		CancelWindow:   time.Duration(b.config.CancelWindowHours) * time.Hour,
+		CancelCooldown: time.Duration(b.config.CancelCooldownHours) * time.Hour,
+	}
+}
+
+// checkQuota проверяет лимиты пользователя на новую запись. Пустые date и carNumber
+// пропускают соответствующие проверки - так лимиты проверяются уже в начале записи.
+// Регулярная серия занимает в лимитах активных записей и записей на номер одно место,
+// иначе постоянный клиент упирается в лимит одной серией. Здесь же применяются меры за неявки.
+// Админы не ограничены
+func (b *CarWashBot) checkQuota(userID int64, date, carNumber string) error {
+	if b.isAdmin(userID) {
+		return nil
+	}
+	if err := b.checkNoShowPolicy(userID, date); err != nil {
+		return err
+	}
+	return b.quotaPolicy().Check(time.Now(), b.quotaUsage(userID, date, carNumber))
+}
+
+// checkSeriesQuota проверяет лимиты перед превращением записи в серию. Серия займёт
+// место исходной записи, поэтому сама исходная запись из подсчёта исключается
+func (b *CarWashBot) checkSeriesQuota(userID int64, booking models.Booking) error {
+	if b.isAdmin(userID) {
+		return nil
+	}
+	if err := b.checkNoShowPolicy(userID, ""); err != nil {
+		return err
+	}
+
+	usage := b.quotaUsage(userID, "", booking.CarNumber)
+	if start, err := booking.StartsAt(); err == nil && start.After(time.Now()) && booking.SeriesID == 0 {
+		usage.Active--
+		usage.OnPlate--
+	}
+	return b.quotaPolicy().Check(time.Now(), usage)
+}
+
+// checkDayQuota проверяет лимиты при выборе дня: для новой записи - все лимиты,
+// для переноса записи rescheduleID - только те, что зависят от дня
+func (b *CarWashBot) checkDayQuota(userID int64, rescheduleID, date string) error {
+	if rescheduleID == "" {
+		return b.checkQuota(userID, date, "")
+	}
+	booking, err := b.storage.GetBookingByID(rescheduleID)
+	if err != nil || booking == nil {
+		// Пропавшую запись отклонит completeReschedule
+		return nil
+	}
+	return b.checkRescheduleQuota(userID, *booking, date)
+}
+
+// checkRescheduleQuota проверяет, можно ли перенести запись на дату date: лимит записей на день
+// и меры за неявки для нового дня. Переносимая запись в подсчёте не учитывается,
+// остальные лимиты при переносе не меняются. Админы не ограничены
+func (b *CarWashBot) checkRescheduleQuota(userID int64, booking models.Booking, date string) error {
+	if b.isAdmin(userID) {
+		return nil
+	}
+	if err := b.checkNoShowPolicy(booking.UserID, date); err != nil {
+		return err
+	}
+
+	usage := b.quotaUsage(booking.UserID, date, "")
+	if start, err := booking.StartsAt(); err == nil && start.After(time.Now()) && booking.Date == date {
+		usage.OnDay--
+	}
+	dayLimit := services.QuotaPolicy{MaxPerDay: b.quotaPolicy().MaxPerDay}
+	return dayLimit.Check(time.Now(), services.QuotaUsage{OnDay: usage.OnDay})
+}
+
+func (b *CarWashBot) quotaUsage(userID int64, date, carNumber string) services.QuotaUsage {
+	now := time.Now()
+	var usage services.QuotaUsage
+
+	bookings, err := b.storage.GetUserBookings(userID)
+	if err != nil {
+		log.Printf("Ошибка получения записей для проверки лимитов: %v", err)
+	}
+	activeSeries := make(map[int64]bool)
+	for _, booking := range bookings {
+		if start, err := booking.StartsAt(); err != nil || !start.After(now) {
+			continue
+		}
+		if booking.SeriesID == 0 {
+			usage.Active++
+		} else if !activeSeries[booking.SeriesID] {
+			activeSeries[booking.SeriesID] = true
+			usage.Active++
+		}
+		if booking.Date == date {
+			usage.OnDay++
+		}
+	}
+
+	if carNumber != "" {
+		plateBookings, err := b.storage.GetBookingsByCarNumber(carNumber)
+		if err != nil {
+			log.Printf("Ошибка получения записей по номеру: %v", err)
+		}
+		plateSeries := make(map[int64]bool)
+		for _, booking := range plateBookings {
+			if start, err := booking.StartsAt(); err != nil || !start.After(now) {
+				continue
+			}
+			if booking.SeriesID == 0 {
+				usage.OnPlate++
+			} else if !plateSeries[booking.SeriesID] {
+				plateSeries[booking.SeriesID] = true
+				usage.OnPlate++
+			}
+		}
+	}
+
+	cancellations, err := b.storage.GetUserActions(userID, models.EventCancelled)
+	if err != nil {
+		log.Printf("Ошибка получения истории отмен: %v", err)
+	}
+	for _, event := range cancellations {
+		usage.Cancellations = append(usage.Cancellations, event.Created)
+	}
+
+	return usage
+}
@@ -0,0 +1,73 @@
+package bot
+
+import (
+	"carwash-bot/config"
+	"carwash-bot/internal/models"
+	"carwash-bot/internal/services"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCheckRescheduleQuota(t *testing.T) {
+	const userID, adminID = 42, 1
+	cfg := &config.Config{
+		AdminID:           adminID,
+		StartTime:         8,
+		EndTime:           20,
+		MaxBookingsPerDay: 1,
+		NoShowLimit:       1,
+		NoShowPenalty:     services.NoShowPenaltyRestrict,
+		NoShowHorizonDays: 3,
+	}
+	b, _ := newTestBot(t, cfg)
+	day := func(offset int) string {
+		return time.Now().AddDate(0, 0, offset).Format("02.01.2006")
+	}
+
+	moved := addTestBooking(t, b, userID, day(1), "10:00", models.BookingActive)
+	addTestBooking(t, b, userID, day(2), "10:00", models.BookingActive)
+
+	tests := []struct {
+		name       string
+		actorID    int64
+		date       string
+		wantReason string // пусто - перенос разрешён
+	}{
+		{"другое время того же дня", userID, day(1), ""},
+		{"свободный день", userID, day(3), ""},
+		{"день с другой записью", userID, day(2), services.ReasonMaxPerDay},
+		{"админ не ограничен лимитом", adminID, day(2), ""},
+	}
+	check := func(t *testing.T, actorID int64, date, wantReason string) {
+		t.Helper()
+		err := b.checkRescheduleQuota(actorID, moved, date)
+		if wantReason == "" {
+			if err != nil {
+				t.Fatalf("checkRescheduleQuota() = %v, ожидалось nil", err)
+			}
+			return
+		}
+		var policyErr *services.PolicyError
+		if !errors.As(err, &policyErr) || policyErr.Reason != wantReason {
+			t.Fatalf("checkRescheduleQuota() = %v, ожидалась причина %q", err, wantReason)
+		}
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check(t, tt.actorID, tt.date, tt.wantReason)
+		})
+	}
+
+	// После неявки запись доступна только на NoShowHorizonDays дней вперёд
+	addTestBooking(t, b, userID, day(-3), "10:00", models.BookingNoShow)
+	t.Run("за горизонтом после неявки", func(t *testing.T) {
+		check(t, userID, day(5), services.ReasonNoShowHorizon)
+	})
+	t.Run("внутри горизонта после неявки", func(t *testing.T) {
+		check(t, userID, day(1), "")
+	})
+	t.Run("админ переносит за горизонт", func(t *testing.T) {
+		check(t, adminID, day(5), "")
+	})
+}
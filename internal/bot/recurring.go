@@ -0,0 +1,383 @@
+package bot
+
+import (
+	"carwash-bot/internal/models"
+	"carwash-bot/internal/services"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// offerRecurring предлагает сделать только что созданную запись регулярной
+func (b *CarWashBot) offerRecurring(chatID int64, bookingID string) {
+	msg := tgbotapi.NewMessage(chatID, b.t(chatID, "recurring.offer"))
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(b.t(chatID, "recurring.make"), "rec_start_"+bookingID),
+		),
+	)
+	b.sendMessageWithSave(chatID, msg)
+}
+
+// seriesRestriction объясняет, почему запись нельзя сделать регулярной, или возвращает пустую строку.
+// Записи серии создаются сразу активными, поэтому серия недоступна тем, чьи записи
+// подтверждает администратор, и не строится от неподтверждённой или неоплаченной записи
+func (b *CarWashBot) seriesRestriction(userID int64, booking models.Booking) string {
+	switch {
+	case booking.SeriesID != 0:
+		return b.t(userID, "recurring.already")
+	case booking.Status != models.BookingActive:
+		return b.t(userID, "recurring.not_active")
+	case b.needsConfirmation(userID):
+		return b.t(userID, "recurring.needs_confirmation")
+	}
+	return ""
+}
+
+// handleRecurringCallback ведёт настройку серии: rec_start_<id> -> rec_freq_<частота> -> rec_count_<n> / rec_until
+func (b *CarWashBot) handleRecurringCallback(chatID, userID int64, messageID int, data string) {
+	state := b.flow.Data(userID)
+
+	switch {
+	case strings.HasPrefix(data, "rec_start_"):
+		bookingID := strings.TrimPrefix(data, "rec_start_")
+		booking, err := b.storage.GetBookingByID(bookingID)
+		if err != nil || booking == nil || booking.UserID != userID {
+			b.sendMessage(chatID, b.t(userID, "cancel.not_found"))
+			return
+		}
+		if reason := b.seriesRestriction(userID, *booking); reason != "" {
+			b.sendMessage(chatID, reason)
+			return
+		}
+
+		b.flow.Reset(userID)
+		if !b.setState(chatID, userID, stateSettingUpSeries, models.UserState{RecurBookingID: bookingID}) {
+			return
+		}
+
+		msg := tgbotapi.NewMessage(chatID, b.t(userID, "recurring.ask_frequency", booking.Time))
+		msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+			tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData(b.t(userID, "recurring.button."+services.FrequencyWeekly), "rec_freq_"+services.FrequencyWeekly),
+			),
+			tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData(b.t(userID, "recurring.button."+services.FrequencyBiweekly), "rec_freq_"+services.FrequencyBiweekly),
+			),
+			tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData(b.t(userID, "recurring.button."+services.FrequencyMonthly), "rec_freq_"+services.FrequencyMonthly),
+			),
+			tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData(b.t(chatID, "menu.main"), "main_menu"),
+			),
+		)
+		b.showScreen(chatID, messageID, msg)
+
+	case strings.HasPrefix(data, "rec_freq_"):
+		frequency := strings.TrimPrefix(data, "rec_freq_")
+		if state.RecurBookingID == "" || services.FrequencyNames[frequency] == "" {
+			b.sendMessage(chatID, b.t(userID, "recurring.outdated"))
+			return
+		}
+		state.RecurFrequency = frequency
+		b.flow.SetData(userID, state)
+
+		msg := tgbotapi.NewMessage(chatID, b.t(userID, "recurring.ask_count"))
+		msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+			tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData("4", "rec_count_4"),
+				tgbotapi.NewInlineKeyboardButtonData("8", "rec_count_8"),
+				tgbotapi.NewInlineKeyboardButtonData("12", "rec_count_12"),
+			),
+			tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData(b.t(userID, "recurring.until_date"), "rec_until"),
+			),
+		)
+		b.showScreen(chatID, messageID, msg)
+
+	case strings.HasPrefix(data, "rec_count_"):
+		count, err := strconv.Atoi(strings.TrimPrefix(data, "rec_count_"))
+		if err != nil || state.RecurBookingID == "" || state.RecurFrequency == "" {
+			b.sendMessage(chatID, b.t(userID, "recurring.outdated"))
+			return
+		}
+		b.flow.Reset(userID)
+		b.createSeries(chatID, userID, state.RecurBookingID, state.RecurFrequency, count, time.Time{})
+
+	case data == "rec_until":
+		if state.RecurBookingID == "" || state.RecurFrequency == "" {
+			b.sendMessage(chatID, b.t(userID, "recurring.outdated"))
+			return
+		}
+		if !b.setState(chatID, userID, stateEnteringRecurUntil, state) {
+			return
+		}
+		msg := tgbotapi.NewMessage(chatID, b.t(userID, "recurring.ask_until"))
+		b.showScreen(chatID, messageID, msg)
+	}
+}
+
+// handleRecurUntilInput принимает дату окончания серии
+func (b *CarWashBot) handleRecurUntilInput(chatID, userID int64, text string) {
+	state := b.flow.Data(userID)
+
+	until, err := time.Parse("02.01.2006", strings.TrimSpace(text))
+	if err != nil {
+		b.sendMessage(chatID, b.t(userID, "recurring.bad_until"))
+		return
+	}
+
+	b.flow.Reset(userID)
+	b.createSeries(chatID, userID, state.RecurBookingID, state.RecurFrequency, 0, until)
+}
+
+// createSeries создаёт записи серии и сообщает о конфликтах
+func (b *CarWashBot) createSeries(chatID, userID int64, bookingID, frequency string, count int, until time.Time) {
+	booking, err := b.storage.GetBookingByID(bookingID)
+	if err != nil || booking == nil || booking.UserID != userID {
+		b.sendMessage(chatID, b.t(userID, "recurring.not_found"))
+		return
+	}
+	if reason := b.seriesRestriction(userID, *booking); reason != "" {
+		b.sendMessage(chatID, reason)
+		return
+	}
+	if err := b.checkSeriesQuota(userID, *booking); err != nil {
+		b.sendMessage(chatID, b.errorText(userID, err))
+		return
+	}
+
+	start, err := time.Parse("02.01.2006", booking.Date)
+	if err != nil {
+		b.sendMessage(chatID, b.t(userID, "policy.bad_date"))
+		return
+	}
+	if !until.IsZero() && !until.After(start) {
+		b.sendMessage(chatID, b.t(userID, "recurring.until_too_early"))
+		return
+	}
+
+	dates := services.RecurrenceDates(start, frequency, count, until, b.config.RecurringMaxOccurrences)
+	if len(dates) == 0 {
+		b.sendMessage(chatID, b.t(userID, "recurring.no_dates"))
+		return
+	}
+
+	series := models.BookingSeries{
+		UserID:    userID,
+		Frequency: frequency,
+		StartDate: booking.Date,
+		Time:      booking.Time,
+		Count:     count,
+		CarModel:  booking.CarModel,
+		CarNumber: booking.CarNumber,
+		Created:   time.Now(),
+	}
+	if !until.IsZero() {
+		series.UntilDate = until.Format("02.01.2006")
+	}
+
+	series.ID, err = b.storage.AddSeries(series)
+	if err != nil {
+		b.sendMessage(chatID, b.t(userID, "recurring.create_error"))
+		return
+	}
+	if err := b.storage.SetBookingSeries(booking.ID, series.ID); err != nil {
+		log.Printf("Ошибка привязки записи к серии: %v", err)
+	}
+
+	created := []models.Booking{*booking}
+	var conflicts []string
+	for _, date := range dates {
+		dateStr := date.Format("02.01.2006")
+
+		if b.isDayClosed(date) {
+			conflicts = append(conflicts, b.t(userID, "recurring.conflict.closed", dateStr))
+			continue
+		}
+		// Повторения подчиняются тем же правилам записи, что и обычная запись, в том числе горизонту
+		if b.checkBookingPolicy(userID, dateStr, booking.Time) != nil {
+			conflicts = append(conflicts, b.t(userID, "recurring.conflict.horizon", dateStr))
+			continue
+		}
+		bay, washerID := b.findSlotPlace(userID, dateStr, booking.Time, booking.Service, "")
+		if bay == 0 {
+			conflicts = append(conflicts, b.t(userID, "recurring.conflict.taken", dateStr))
+			continue
+		}
+		if !b.isAdmin(userID) && b.checkNoShowPolicy(userID, dateStr) != nil {
+			conflicts = append(conflicts, b.t(userID, "recurring.conflict.horizon", dateStr))
+			continue
+		}
+		if maxPerDay := b.quotaPolicy().MaxPerDay; !b.isAdmin(userID) && maxPerDay > 0 &&
+			b.quotaUsage(userID, dateStr, "").OnDay >= maxPerDay {
+			conflicts = append(conflicts, b.t(userID, "recurring.conflict.day_limit", dateStr))
+			continue
+		}
+
+		price, err := b.slotPrice(dateStr, booking.Time, booking.Service, booking.CarClass)
+		if err != nil {
+			price = booking.Price
+		}
+		// Счёт выставляется на каждую запись отдельно, поэтому время, требующее
+		// предоплаты, в серию не попадает
+		slotState := models.UserState{SelectedDate: dateStr, SelectedTime: booking.Time,
+			SelectedService: booking.Service, SelectedClass: booking.CarClass}
+		if b.needsPrepayment(userID, slotState, price) {
+			conflicts = append(conflicts, b.t(userID, "recurring.conflict.prepayment", dateStr))
+			continue
+		}
+		duration, buffer := b.bookingTiming(booking.Service, bay)
+
+		occurrence := models.Booking{
+			ID:        b.newBookingID(userID, dateStr, booking.Time),
+			Date:      dateStr,
+			Time:      booking.Time,
+			CarModel:  booking.CarModel,
+			CarNumber: booking.CarNumber,
+			UserID:    userID,
+			Created:   time.Now(),
+			SeriesID:  series.ID,
+			Service:   booking.Service,
+			CarClass:  booking.CarClass,
+			Price:     price,
+			Duration:  duration,
+			Buffer:    buffer,
+			Bay:       bay,
+			WasherID:  washerID,
+			Status:    models.BookingActive,
+		}
+		if err := b.storage.AddBooking(occurrence); err != nil {
+			log.Printf("Ошибка создания записи серии: %v", err)
+			conflicts = append(conflicts, b.t(userID, "recurring.conflict.save_error", dateStr))
+			continue
+		}
+		b.logBookingEvent(occurrence, models.EventCreated, fmt.Sprintf("%s %s, серия #%d", dateStr, booking.Time, series.ID), userID)
+		b.scheduleBookingJobs(occurrence)
+		created = append(created, occurrence)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(b.t(userID, "recurring.created",
+		b.t(userID, "recurring.freq."+frequency), booking.Time, len(created)))
+	for _, occurrence := range created {
+		sb.WriteString(fmt.Sprintf("📅 %s — %s\n", occurrence.Date, b.pricing.FormatPrice(occurrence.Price)))
+	}
+	if len(conflicts) > 0 {
+		sb.WriteString(b.t(userID, "recurring.conflicts", len(conflicts)))
+		for _, conflict := range conflicts {
+			sb.WriteString(conflict + "\n")
+		}
+	}
+	sb.WriteString(b.t(userID, "recurring.cancel_hint"))
+	b.sendMessage(chatID, sb.String())
+
+	if b.config.ChannelID != 0 {
+		if err := b.notifyChannelAboutSeries(series, created); err != nil {
+			log.Printf("Ошибка оповещения канала: %v", err)
+		}
+	}
+}
+
+func (b *CarWashBot) notifyChannelAboutSeries(series models.BookingSeries, bookings []models.Booking) error {
+	var dates []string
+	for _, booking := range bookings {
+		dates = append(dates, booking.Date)
+	}
+
+	msg := tgbotapi.NewMessage(b.config.ChannelID, fmt.Sprintf(`🔁 Новая регулярная запись:
+🕒 <code>%s</code>, %s
+📅 %s
+🚗 <i>%s %s</i>
+👤 ID: %d`,
+		series.Time,
+		services.FrequencyNames[series.Frequency],
+		strings.Join(dates, ", "),
+		series.CarModel,
+		series.CarNumber,
+		series.UserID))
+	msg.ParseMode = "HTML"
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("❌ Отменить серию", fmt.Sprintf("admin_series_cancel:%d", series.ID)),
+		),
+	)
+
+	_, err := b.botAPI.Send(msg)
+	return err
+}
+
+// cancelSeries отменяет все будущие записи серии. Пользователь не может отменить
+// записи, для которых уже действует запрет на отмену, и оплаченные записи; админ отменяет всё.
+// Возвращает количество отменённых и пропущенных записей.
+func (b *CarWashBot) cancelSeries(userID, seriesID int64) (cancelled, skipped int, err error) {
+	series, err := b.storage.GetSeries(seriesID)
+	if err != nil {
+		return 0, 0, err
+	}
+	if series == nil || (series.UserID != userID && !b.isAdmin(userID)) {
+		return 0, 0, fmt.Errorf("серия %d не найдена", seriesID)
+	}
+
+	bookings, err := b.storage.GetSeriesBookings(seriesID)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	now := time.Now()
+	for _, booking := range bookings {
+		start, err := booking.StartsAt()
+		if err != nil || !start.After(now) {
+			continue
+		}
+		if b.checkChangePolicy(userID, booking) != nil {
+			skipped++
+			continue
+		}
+		// Оплаченную запись с возвратом предоплаты снимает только администратор
+		if !b.isAdmin(userID) && b.bookingPayment(booking) != nil {
+			skipped++
+			continue
+		}
+		if err := b.storage.DeleteBooking(booking.ID); err != nil {
+			log.Printf("Ошибка отмены записи серии: %v", err)
+			skipped++
+			continue
+		}
+		cancelled++
+		b.logBookingEvent(booking, models.EventSeriesCancelled, fmt.Sprintf("%s %s, серия #%d", booking.Date, booking.Time, seriesID), userID)
+		b.refundLoyaltyPoints(booking, userID)
+		b.refundPrepayment(booking, userID)
+		b.offerFreedSlot(booking.Date, booking.Time)
+	}
+	return cancelled, skipped, nil
+}
+
+func (b *CarWashBot) handleSeriesCancellation(chatID, userID int64, idStr string) {
+	seriesID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		b.sendMessage(chatID, b.t(userID, "error.format"))
+		return
+	}
+
+	cancelled, skipped, err := b.cancelSeries(userID, seriesID)
+	if err != nil {
+		b.sendMessage(chatID, b.t(userID, "recurring.cancel_error"))
+		return
+	}
+
+	text := b.t(userID, "recurring.cancelled", cancelled)
+	if skipped > 0 {
+		text += b.t(userID, "recurring.cancel_skipped", skipped)
+	}
+	b.sendMessage(chatID, text)
+
+	if userID != b.adminID {
+		b.sendMessage(b.adminID, fmt.Sprintf("ℹ️ Пользователь %d отменил регулярную запись #%d (%d записей)",
+			userID, seriesID, cancelled))
+	}
+}
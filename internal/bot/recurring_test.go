@@ -0,0 +1,40 @@
+package bot
+
+import (
+	"carwash-bot/config"
+	"carwash-bot/internal/models"
+	"carwash-bot/internal/services"
+	"testing"
+	"time"
+)
+
+// Повторения серии не выходят за горизонт записи
+func TestCreateSeriesRespectsHorizon(t *testing.T) {
+	const userID = 42
+	cfg := &config.Config{
+		AdminID:                 1,
+		StartTime:               8,
+		EndTime:                 20,
+		SlotStepMinutes:         60,
+		Bays:                    1,
+		BookingHorizonDays:      14,
+		RecurringMaxOccurrences: 52,
+	}
+	b, _ := newTestBot(t, cfg)
+
+	first := addTestBooking(t, b, userID, time.Now().AddDate(0, 0, 1).Format("02.01.2006"), "10:00", models.BookingActive)
+	b.createSeries(userID, userID, first.ID, services.FrequencyWeekly, 12, time.Time{})
+
+	bookings, err := b.storage.GetUserBookings(userID)
+	if err != nil {
+		t.Fatalf("GetUserBookings: %v", err)
+	}
+	// Исходная запись и повторение через неделю, следующее уже за горизонтом в 14 дней
+	if len(bookings) != 2 {
+		var dates []string
+		for _, booking := range bookings {
+			dates = append(dates, booking.Date)
+		}
+		t.Errorf("создано записей: %d (%v), ожидалось 2", len(bookings), dates)
+	}
+}
@@ -0,0 +1,200 @@
+package bot
+
+import (
+	"carwash-bot/internal/models"
+	"log"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// scheduleBookingJobs планирует задачи по записи: напоминания и просьбу оценить мойку
+func (b *CarWashBot) scheduleBookingJobs(booking models.Booking) {
+	b.scheduleReminders(booking)
+	b.scheduleFeedbackRequest(booking)
+}
+
+// scheduleReminders планирует напоминания о записи за REMINDER_MINUTES до начала.
+// Напоминания, время которых уже прошло, не планируются
+func (b *CarWashBot) scheduleReminders(booking models.Booking) {
+	start, err := booking.StartsAt()
+	if err != nil {
+		log.Printf("Ошибка планирования напоминаний для %s: %v", booking.ID, err)
+		return
+	}
+
+	now := time.Now()
+	for _, minutes := range b.config.ReminderMinutes {
+		runAt := start.Add(-time.Duration(minutes) * time.Minute)
+		if minutes <= 0 || !runAt.After(now) {
+			continue
+		}
+		job := models.Job{
+			Kind:          models.JobReminder,
+			BookingID:     booking.ID,
+			Slot:          booking.Date + " " + booking.Time,
+			OffsetMinutes: minutes,
+			RunAt:         runAt,
+		}
+		if err := b.storage.ScheduleJob(job); err != nil {
+			log.Printf("Ошибка планирования напоминания для %s: %v", booking.ID, err)
+		}
+	}
+}
+
+// scheduleUpcomingJobs планирует задачи для всех будущих записей.
+// Повторное планирование ничего не дублирует, поэтому вызывается при каждом запуске
+func (b *CarWashBot) scheduleUpcomingJobs() {
+	bookings, err := b.storage.GetAllBookings()
+	if err != nil {
+		log.Printf("Ошибка получения записей для планирования задач: %v", err)
+		return
+	}
+	for _, booking := range bookings {
+		if booking.Status == models.BookingActive || booking.Status == models.BookingPending {
+			b.scheduleBookingJobs(booking)
+		}
+	}
+}
+
+// runJobScheduler раз в минуту выполняет задачи, время которых наступило.
+// Задачи хранятся в базе, поэтому пропущенные во время простоя выполняются после запуска
+func (b *CarWashBot) runJobScheduler() {
+	// Задача, прерванная перезапуском, выполнится ещё раз: лучше повторное
+	// напоминание, чем потерянное
+	if n, err := b.storage.ResetRunningJobs(); err != nil {
+		log.Printf("Ошибка возврата прерванных задач: %v", err)
+	} else if n > 0 {
+		log.Printf("Возвращено в очередь прерванных задач: %d", n)
+	}
+	b.scheduleUpcomingJobs()
+
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		b.runDueJobs()
+		<-ticker.C
+	}
+}
+
+func (b *CarWashBot) runDueJobs() {
+	jobs, err := b.storage.DueJobs(time.Now())
+	if err != nil {
+		log.Printf("Ошибка получения задач: %v", err)
+		return
+	}
+
+	for _, job := range jobs {
+		claimed, err := b.storage.ClaimJob(job.ID)
+		if err != nil {
+			log.Printf("Ошибка запуска задачи %d: %v", job.ID, err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		status := models.JobSkipped
+		switch job.Kind {
+		case models.JobReminder:
+			if b.sendReminder(job) {
+				status = models.JobDone
+			}
+		case models.JobFeedback:
+			if b.sendFeedbackRequest(job) {
+				status = models.JobDone
+			}
+		case models.JobPaymentExpiry:
+			if b.expireUnpaidBooking(job) {
+				status = models.JobDone
+			}
+		default:
+			log.Printf("Неизвестный вид задачи %q", job.Kind)
+		}
+
+		if err := b.storage.SetJobStatus(job.ID, status); err != nil {
+			log.Printf("Ошибка обновления задачи %d: %v", job.ID, err)
+		}
+	}
+}
+
+// sendReminder отправляет напоминание о записи. Возвращает false, если запись
+// отменена, не подтверждена администратором, перенесена на другое время или уже началась
+func (b *CarWashBot) sendReminder(job models.Job) bool {
+	booking, err := b.storage.GetBookingByID(job.BookingID)
+	if err != nil {
+		log.Printf("Ошибка получения записи %s: %v", job.BookingID, err)
+		return false
+	}
+	if booking == nil || booking.Status != models.BookingActive || booking.Date+" "+booking.Time != job.Slot {
+		return false
+	}
+	start, err := booking.StartsAt()
+	if err != nil || !time.Now().Before(start) {
+		return false
+	}
+	// После простоя бота могли наступить сразу несколько напоминаний - отправляем только ближайшее
+	for _, minutes := range b.config.ReminderMinutes {
+		if minutes > 0 && minutes < job.OffsetMinutes && time.Until(start) <= time.Duration(minutes)*time.Minute {
+			return false
+		}
+	}
+
+	userID := booking.UserID
+	text := b.t(userID, "reminder.text", b.timeUntil(userID, time.Until(start)),
+		booking.Date, booking.Time, booking.CarModel, booking.CarNumber)
+
+	msg := tgbotapi.NewMessage(userID, text)
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(b.t(userID, "reminder.coming"), "rem_ok:"+booking.ID),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(b.t(userID, "reminder.cancel"), "cancel_"+booking.ID),
+			tgbotapi.NewInlineKeyboardButtonData(b.t(userID, "reminder.reschedule"), "resched_"+booking.ID),
+		),
+	)
+	// Если отправить не удалось (например, бот заблокирован), повторять не нужно
+	if _, err := b.botAPI.Send(msg); err != nil {
+		log.Printf("Ошибка отправки напоминания по записи %s: %v", booking.ID, err)
+	}
+	return true
+}
+
+// timeUntil описывает, сколько осталось до мойки: "через 2 часа", "через 40 минут"
+func (b *CarWashBot) timeUntil(userID int64, d time.Duration) string {
+	minutes := int(d.Round(time.Minute).Minutes())
+	if minutes >= 60 {
+		hours := (minutes + 30) / 60
+		return b.tn(userID, "reminder.in_hours", hours, hours)
+	}
+	return b.tn(userID, "reminder.in_minutes", minutes, minutes)
+}
+
+// handleReminderCallback отмечает, что клиент приедет: rem_ok:<id записи>
+func (b *CarWashBot) handleReminderCallback(query *tgbotapi.CallbackQuery) {
+	userID := query.From.ID
+	bookingID := strings.TrimPrefix(query.Data, "rem_ok:")
+
+	booking, err := b.storage.GetBookingByID(bookingID)
+	if err != nil || booking == nil || booking.UserID != userID {
+		b.sendMessage(query.Message.Chat.ID, b.t(userID, "cancel.not_found"))
+		return
+	}
+	b.logBookingEvent(*booking, models.EventVisitConfirmed, booking.Date+" "+booking.Time, userID)
+
+	// Кнопки отмены и переноса остаются: планы могут измениться и после подтверждения
+	text := query.Message.Text + "\n\n" + b.t(userID, "reminder.confirmed")
+	editMsg := tgbotapi.NewEditMessageTextAndMarkup(query.Message.Chat.ID, query.Message.MessageID, text,
+		tgbotapi.NewInlineKeyboardMarkup(
+			tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData(b.t(userID, "reminder.cancel"), "cancel_"+booking.ID),
+				tgbotapi.NewInlineKeyboardButtonData(b.t(userID, "reminder.reschedule"), "resched_"+booking.ID),
+			),
+		))
+	if _, err := b.botAPI.Send(editMsg); err != nil {
+		log.Printf("Ошибка обновления напоминания: %v", err)
+	}
+}
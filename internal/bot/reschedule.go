@@ -0,0 +1,175 @@
+package bot
+
+import (
+	"carwash-bot/internal/models"
+	"carwash-bot/storage"
+	"fmt"
+	"log"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// startReschedule запускает выбор нового времени для существующей записи.
+// chatID - личный чат того, кто переносит (владельца или админа)
+func (b *CarWashBot) startReschedule(chatID, userID int64, bookingID string) {
+	booking, err := b.storage.GetBookingByID(bookingID)
+	if err != nil {
+		b.sendMessage(chatID, b.t(userID, "reschedule.error"))
+		return
+	}
+	if booking == nil {
+		b.sendMessage(chatID, b.t(userID, "cancel.not_found"))
+		return
+	}
+	if booking.UserID != userID && !b.isAdmin(userID) {
+		b.sendMessage(chatID, b.t(userID, "cancel.not_yours"))
+		return
+	}
+	if booking.Status == models.BookingAwaitingPayment {
+		b.sendMessage(chatID, b.t(userID, "reschedule.unpaid"))
+		return
+	}
+	if err := b.checkChangePolicy(userID, *booking); err != nil {
+		b.sendMessage(chatID, b.errorText(userID, err))
+		return
+	}
+	if !b.isAdmin(userID) && b.bookingPayment(*booking) != nil {
+		b.sendMessage(chatID, b.t(userID, "payment.reschedule_paid"))
+		return
+	}
+
+	b.flow.Reset(userID)
+	if !b.setState(chatID, userID, stateChoosingDay, models.UserState{RescheduleID: booking.ID, SelectedService: booking.Service}) {
+		return
+	}
+
+	b.sendMessage(chatID, b.t(userID, "reschedule.start",
+		booking.Date, booking.Time, booking.CarModel, booking.CarNumber))
+	b.showDaySelection(chatID)
+}
+
+// completeReschedule переносит запись на выбранное время
+func (b *CarWashBot) completeReschedule(chatID, userID int64, newDate, newTime string) {
+	state := b.flow.Data(userID)
+	b.flow.Reset(userID)
+
+	booking, err := b.storage.GetBookingByID(state.RescheduleID)
+	if err != nil || booking == nil {
+		b.sendMessage(chatID, b.t(userID, "reschedule.not_found"))
+		return
+	}
+
+	if booking.Date == newDate && booking.Time == newTime {
+		b.sendMessage(chatID, b.t(userID, "reschedule.same_time"))
+		return
+	}
+
+	// Правила могли сработать, пока пользователь выбирал время
+	if err := b.checkChangePolicy(userID, *booking); err != nil {
+		b.sendMessage(chatID, b.errorText(userID, err))
+		return
+	}
+	if err := b.checkRescheduleQuota(userID, *booking, newDate); err != nil {
+		b.sendMessage(chatID, b.errorText(userID, err))
+		return
+	}
+
+	bay, washerID := b.findSlotPlace(booking.UserID, newDate, newTime, booking.Service, booking.ID)
+	if bay == 0 {
+		b.sendMessage(chatID, b.t(userID, "reschedule.taken"))
+		b.retryReschedule(chatID, userID, *booking, newDate)
+		return
+	}
+
+	// Цена пересчитывается по правилам для нового времени
+	oldPrice := booking.Price
+	newPrice, err := b.slotPrice(newDate, newTime, booking.Service, booking.CarClass)
+	if err != nil {
+		log.Printf("Ошибка расчёта цены: %v", err)
+		newPrice = oldPrice
+	}
+	// Бесплатная мойка за баллы остаётся бесплатной
+	if booking.LoyaltyPoints > 0 {
+		newPrice = 0
+	}
+	promoCode, discount := b.rescheduleDiscount(*booking, newDate, newTime, newPrice)
+	newPrice -= discount
+	// Оплаченная запись переносится по уже оплаченной цене
+	if b.bookingPayment(*booking) != nil {
+		newPrice, promoCode, discount = oldPrice, booking.PromoCode, booking.Discount
+	}
+
+	oldDate, oldTime := booking.Date, booking.Time
+	_, buffer := b.bookingTiming(booking.Service, bay)
+	moved := *booking
+	moved.Date, moved.Time, moved.Bay, moved.Buffer, moved.Price = newDate, newTime, bay, buffer, newPrice
+	moved.WasherID, moved.PromoCode, moved.Discount = washerID, promoCode, discount
+	if err := b.storage.MoveBooking(moved); err != nil {
+		if err == storage.ErrSlotTaken {
+			b.sendMessage(chatID, b.t(userID, "reschedule.just_taken"))
+			b.retryReschedule(chatID, userID, *booking, newDate)
+			return
+		}
+		b.sendMessage(chatID, b.t(userID, "reschedule.failed"))
+		return
+	}
+	booking = &moved
+
+	b.logBookingEvent(*booking, models.EventRescheduled,
+		fmt.Sprintf("%s %s -> %s %s", oldDate, oldTime, newDate, newTime), userID)
+	b.scheduleBookingJobs(*booking)
+
+	if err := b.storage.CompleteWaitlistEntries(booking.UserID, newDate); err != nil {
+		log.Printf("Ошибка обновления листа ожидания: %v", err)
+	}
+
+	text := b.t(userID, "reschedule.done",
+		oldDate, oldTime, newDate, newTime, booking.CarModel, booking.CarNumber)
+	if newPrice != oldPrice {
+		text += b.t(userID, "reschedule.price_changed",
+			b.pricing.FormatPrice(oldPrice), b.pricing.FormatPrice(newPrice))
+	}
+	b.sendMessage(chatID, text)
+
+	// Если переносил админ - сообщаем владельцу записи
+	if booking.UserID != userID {
+		b.sendMessage(booking.UserID, b.t(booking.UserID, "reschedule.by_admin",
+			oldDate, oldTime, newDate, newTime, booking.CarModel, booking.CarNumber))
+	} else {
+		b.notifyAdmins(fmt.Sprintf("🔁 Пользователь перенёс запись:\n%s %s → %s %s\n🚗 %s %s",
+			oldDate, oldTime, newDate, newTime, booking.CarModel, booking.CarNumber))
+	}
+
+	if b.config.ChannelID != 0 {
+		if err := b.notifyChannelAboutReschedule(*booking, oldDate, oldTime); err != nil {
+			log.Printf("Ошибка оповещения канала: %v", err)
+		}
+	}
+
+	// Старое время освободилось
+	b.offerFreedSlot(oldDate, oldTime)
+}
+
+// retryReschedule возвращает к выбору времени, если выбранное время заняли
+func (b *CarWashBot) retryReschedule(chatID, userID int64, booking models.Booking, date string) {
+	state := models.UserState{RescheduleID: booking.ID, SelectedDate: date, SelectedService: booking.Service}
+	if b.setState(chatID, userID, stateChoosingTime, state) {
+		b.showTimeSlots(chatID, userID, 0, date)
+	}
+}
+
+func (b *CarWashBot) notifyChannelAboutReschedule(booking models.Booking, oldDate, oldTime string) error {
+	msg := tgbotapi.NewMessage(b.config.ChannelID, fmt.Sprintf(`🔁 Запись перенесена:
+📅 <s>%s %s</s> → <b>%s</b> в <code>%s</code>
+🚗 <i>%s %s</i>
+👤 ID: %d`,
+		oldDate, oldTime,
+		booking.Date, booking.Time,
+		booking.CarModel, booking.CarNumber,
+		booking.UserID))
+	msg.ParseMode = "HTML"
+	msg.ReplyMarkup = b.channelBookingButtons(booking)
+
+	_, err := b.botAPI.Send(msg)
+	return err
+}
@@ -0,0 +1,301 @@
+package bot
+
+import (
+	"carwash-bot/internal/models"
+	"carwash-bot/internal/services"
+	"fmt"
+	"log"
+	"time"
+)
+
+// slotTimes возвращает все времена начала моек за рабочий день с шагом SLOT_STEP_MINUTES
+func (b *CarWashBot) slotTimes() []string {
+	step := b.config.SlotStepMinutes
+	if step <= 0 {
+		step = 60
+	}
+
+	var times []string
+	for minutes := b.storage.StartTime * 60; minutes <= b.storage.EndTime*60; minutes += step {
+		times = append(times, fmt.Sprintf("%02d:%02d", minutes/60, minutes%60))
+	}
+	return times
+}
+
+// isSlotOnGrid проверяет, что мойка работает в этот день, а время есть в сетке slotTimes.
+// Дата и время приходят из данных кнопок и ссылок /start, которые можно подделать
+func (b *CarWashBot) isSlotOnGrid(dateStr, timeStr string) bool {
+	date, err := time.Parse("02.01.2006", dateStr)
+	if err != nil || b.isDayClosed(date) {
+		return false
+	}
+	for _, slot := range b.slotTimes() {
+		if slot == timeStr {
+			return true
+		}
+	}
+	return false
+}
+
+// bays возвращает посты мойки с их временем на уборку
+func (b *CarWashBot) bays() []services.Bay {
+	count := b.config.Bays
+	if count <= 0 {
+		count = 1
+	}
+
+	bays := make([]services.Bay, count)
+	for i := range bays {
+		buffer := b.config.BufferMinutes
+		if i < len(b.config.BayBuffers) {
+			buffer = b.config.BayBuffers[i]
+		}
+		bays[i] = services.Bay{Number: i + 1, Buffer: time.Duration(buffer) * time.Minute}
+	}
+	return bays
+}
+
+// serviceTiming возвращает длительность услуги и её собственное время на уборку
+func (b *CarWashBot) serviceTiming(code string) (time.Duration, time.Duration) {
+	service, ok := b.pricing.Service(code)
+	if !ok {
+		service = b.pricing.DefaultService()
+	}
+	return time.Duration(service.Duration) * time.Minute, time.Duration(service.Buffer) * time.Minute
+}
+
+// bookingOccupancy возвращает интервал, на который запись занимает пост, вместе с уборкой
+func bookingOccupancy(booking models.Booking) (services.Occupied, error) {
+	start, err := booking.StartsAt()
+	if err != nil {
+		return services.Occupied{}, err
+	}
+
+	duration := booking.Duration
+	if duration <= 0 {
+		duration = services.DefaultServiceDuration
+	}
+	bay := booking.Bay
+	if bay <= 0 {
+		bay = 1
+	}
+	end := start.Add(time.Duration(duration+booking.Buffer) * time.Minute)
+	return services.Occupied{Bay: bay, Start: start, End: end}, nil
+}
+
+// dayOccupancy собирает занятость постов и мойщиков на дату: записи (кроме excludeID и неявок)
+// и клиенты живой очереди, которые сейчас моются
+func (b *CarWashBot) dayOccupancy(dateStr, excludeID string) ([]services.Occupied, []services.Assignment, error) {
+	bookings, err := b.storage.GetBookingsByDate(dateStr)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var occupied []services.Occupied
+	var assignments []services.Assignment
+	for _, booking := range bookings {
+		if booking.ID == excludeID || booking.Status == models.BookingNoShow {
+			continue
+		}
+		o, err := bookingOccupancy(booking)
+		if err != nil {
+			continue
+		}
+		occupied = append(occupied, o)
+		if booking.WasherID != 0 {
+			assignments = append(assignments, services.Assignment{StaffID: booking.WasherID, Start: o.Start, End: o.End})
+		}
+	}
+
+	if dateStr == time.Now().Format("02.01.2006") {
+		walkIns, err := b.walkInOccupancy()
+		if err != nil {
+			return nil, nil, err
+		}
+		occupied = append(occupied, walkIns...)
+	}
+	return occupied, assignments, nil
+}
+
+// slotDay - занятость постов и мойщиков, смены и время, удерживаемое листом ожидания, на одну дату.
+// Загружается один раз, чтобы проверять все времена дня без повторных запросов к базе
+type slotDay struct {
+	date        string
+	occupied    []services.Occupied
+	assignments []services.Assignment
+	holds       []models.WaitlistEntry
+	staffed     bool
+	shifts      []services.ShiftWindow
+}
+
+// loadSlotDay собирает занятость на дату. Запись excludeID не учитывается
+// (при переносе она не мешает сама себе)
+func (b *CarWashBot) loadSlotDay(dateStr, excludeID string) (*slotDay, error) {
+	day := &slotDay{date: dateStr}
+
+	var err error
+	if day.occupied, day.assignments, err = b.dayOccupancy(dateStr, excludeID); err != nil {
+		return nil, fmt.Errorf("записи: %w", err)
+	}
+	if day.holds, err = b.storage.GetActiveHolds(dateStr, time.Now()); err != nil {
+		return nil, fmt.Errorf("лист ожидания: %w", err)
+	}
+	if day.staffed, day.shifts, err = b.shiftWindows(dateStr); err != nil {
+		return nil, fmt.Errorf("смены: %w", err)
+	}
+	return day, nil
+}
+
+// findSlotPlace подбирает пост и мойщика для услуги на указанное время, bay = 0 - места нет.
+// Если мойщики не заведены, мойщик не назначается (washerID = 0) и число моек ограничено только постами.
+// Запись excludeID не учитывается (при переносе она не мешает сама себе),
+// а время, удерживаемое листом ожидания за другими, занимает одно из мест
+func (b *CarWashBot) findSlotPlace(userID int64, dateStr, timeStr, service, excludeID string) (bay int, washerID int64) {
+	day, err := b.loadSlotDay(dateStr, excludeID)
+	if err != nil {
+		log.Printf("Ошибка получения занятости на %s: %v", dateStr, err)
+		return 0, 0
+	}
+	return b.placeInDay(day, userID, timeStr, service)
+}
+
+// placeInDay подбирает пост и мойщика по уже загруженной занятости дня
+func (b *CarWashBot) placeInDay(day *slotDay, userID int64, timeStr, service string) (bay int, washerID int64) {
+	start, err := models.SlotTime(day.date, timeStr)
+	if err != nil {
+		return 0, 0
+	}
+
+	duration, buffer := b.serviceTiming(service)
+	bays := b.bays()
+	free := services.FreeBays(day.occupied, bays, start, duration, buffer)
+	if len(free) == 0 {
+		return 0, 0
+	}
+
+	held := 0
+	for _, hold := range day.holds {
+		if hold.OfferedTime == timeStr && hold.UserID != userID {
+			held++
+		}
+	}
+	if len(free) <= held {
+		return 0, 0
+	}
+	bay = free[held]
+
+	if !day.staffed {
+		return bay, 0
+	}
+
+	end := start.Add(duration + services.BufferFor(bays, bay, buffer))
+	washers := services.FreeWashers(day.shifts, day.assignments, start, end)
+	if len(washers) <= held {
+		return 0, 0
+	}
+	return bay, washers[held]
+}
+
+// shiftWindows возвращает смены мойщиков на дату. staffed = false, если мойщики не заведены
+// и число моек не ограничивается сменами
+func (b *CarWashBot) shiftWindows(dateStr string) (staffed bool, windows []services.ShiftWindow, err error) {
+	staff, err := b.storage.ListStaff()
+	if err != nil || len(staff) == 0 {
+		return false, nil, err
+	}
+
+	shifts, err := b.storage.GetShiftsByDate(dateStr)
+	if err != nil {
+		return true, nil, err
+	}
+	for _, shift := range shifts {
+		start, err := models.SlotTime(shift.Date, shift.Start)
+		if err != nil {
+			continue
+		}
+		end, err := models.SlotTime(shift.Date, shift.End)
+		if err != nil {
+			continue
+		}
+		windows = append(windows, services.ShiftWindow{StaffID: shift.StaffID, Start: start, End: end})
+	}
+	return true, windows, nil
+}
+
+// isDayClosed проверяет, работает ли мойка в указанный день
+func (b *CarWashBot) isDayClosed(date time.Time) bool {
+	for _, weekday := range b.config.ClosedWeekdays {
+		if int(date.Weekday()) == weekday {
+			return true
+		}
+	}
+	dateStr := date.Format("02.01.2006")
+	for _, closed := range b.config.ClosedDates {
+		if closed == dateStr {
+			return true
+		}
+	}
+	return false
+}
+
+// isWithinHorizon проверяет, что дата не в прошлом и не дальше горизонта записи
+func (b *CarWashBot) isWithinHorizon(date time.Time) bool {
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	last := today.AddDate(0, 0, b.bookingPolicy().MaxAdvanceDays-1)
+	return !day.Before(today) && !day.After(last)
+}
+
+// bookingTiming возвращает длительность услуги и время уборки в минутах для записи на посту bay
+func (b *CarWashBot) bookingTiming(service string, bay int) (int, int) {
+	duration, buffer := b.serviceTiming(service)
+	buffer = services.BufferFor(b.bays(), bay, buffer)
+	return int(duration / time.Minute), int(buffer / time.Minute)
+}
+
+// isSlotOpen проверяет, можно ли записаться на услугу в указанное время с учётом правил записи
+func (b *CarWashBot) isSlotOpen(dateStr, timeStr, service string) bool {
+	start, err := models.SlotTime(dateStr, timeStr)
+	if err != nil || b.bookingPolicy().CheckBooking(time.Now(), start) != nil {
+		return false
+	}
+
+	return b.isSlotFreeFor(0, dateStr, timeStr, service)
+}
+
+// isSlotFreeFor проверяет, что для услуги найдётся свободный пост и мойщик на смене
+// с учётом уборки после моек и времени, удерживаемого листом ожидания за другими
+func (b *CarWashBot) isSlotFreeFor(userID int64, dateStr, timeStr, service string) bool {
+	bay, _ := b.findSlotPlace(userID, dateStr, timeStr, service, "")
+	return bay > 0
+}
+
+// openSlotTimes возвращает времена дня, на которые можно записаться на услугу,
+// как isSlotOpen для каждого слота. Занятость дня загружается один раз
+func (b *CarWashBot) openSlotTimes(dateStr, service string) map[string]bool {
+	day, err := b.loadSlotDay(dateStr, "")
+	if err != nil {
+		log.Printf("Ошибка получения занятости на %s: %v", dateStr, err)
+		return nil
+	}
+
+	now := time.Now()
+	policy := b.bookingPolicy()
+	open := make(map[string]bool)
+	for _, timeStr := range b.slotTimes() {
+		start, err := models.SlotTime(dateStr, timeStr)
+		if err != nil || policy.CheckBooking(now, start) != nil {
+			continue
+		}
+		if bay, _ := b.placeInDay(day, 0, timeStr, service); bay > 0 {
+			open[timeStr] = true
+		}
+	}
+	return open
+}
+
+// freeSlotsCount считает свободные слоты на день для услуги
+func (b *CarWashBot) freeSlotsCount(dateStr, service string) int {
+	return len(b.openSlotTimes(dateStr, service))
+}
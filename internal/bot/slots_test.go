@@ -0,0 +1,32 @@
+package bot
+
+import (
+	"carwash-bot/config"
+	"carwash-bot/internal/models"
+	"testing"
+	"time"
+)
+
+// Время дня, загруженное разом, совпадает с проверкой каждого слота по отдельности
+func TestOpenSlotTimes(t *testing.T) {
+	cfg := &config.Config{AdminID: 1, StartTime: 8, EndTime: 20, SlotStepMinutes: 60, Bays: 1}
+	b, _ := newTestBot(t, cfg)
+	date := time.Now().AddDate(0, 0, 1).Format("02.01.2006")
+	service := b.pricing.DefaultService().Code
+
+	addTestBooking(t, b, 42, date, "10:00", models.BookingActive)
+	addTestBooking(t, b, 43, date, "15:00", models.BookingActive)
+
+	open := b.openSlotTimes(date, service)
+	if open["10:00"] || open["15:00"] {
+		t.Errorf("занятое время считается свободным: %v", open)
+	}
+	for _, timeStr := range b.slotTimes() {
+		if got, want := open[timeStr], b.isSlotOpen(date, timeStr, service); got != want {
+			t.Errorf("%s: openSlotTimes = %v, isSlotOpen = %v", timeStr, got, want)
+		}
+	}
+	if got := b.freeSlotsCount(date, service); got != len(open) || got == 0 {
+		t.Errorf("freeSlotsCount() = %d, свободно %d", got, len(open))
+	}
+}
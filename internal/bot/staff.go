@@ -0,0 +1,338 @@
+package bot
+
+import (
+	"carwash-bot/internal/models"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// handleStaffCommand управляет списком мойщиков:
+// /staff, /staff add <telegram_id> <имя>, /staff remove <id>
+func (b *CarWashBot) handleStaffCommand(chatID, userID int64, text string) {
+	if !b.isAdmin(userID) {
+		b.sendMessage(chatID, "❌ Команда доступна только администратору")
+		return
+	}
+
+	args := strings.Fields(text)[1:]
+	if len(args) == 0 {
+		b.showStaff(chatID)
+		return
+	}
+
+	usage := "Использование:\n/staff add <telegram_id> <имя>\n/staff remove <id>\n\n" +
+		"Telegram ID нужен, чтобы мойщик видел свою очередь. Если его нет, укажите 0."
+
+	switch args[0] {
+	case "add":
+		if len(args) < 3 {
+			b.sendMessage(chatID, usage)
+			return
+		}
+		telegramID, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			b.sendMessage(chatID, usage)
+			return
+		}
+		staff := models.Staff{
+			TelegramID: telegramID,
+			Name:       strings.Join(args[2:], " "),
+			Created:    time.Now(),
+		}
+		id, err := b.storage.AddStaff(staff)
+		if err != nil {
+			b.sendMessage(chatID, "⚠️ Не удалось добавить мойщика")
+			return
+		}
+		b.sendMessage(chatID, fmt.Sprintf("✅ Мойщик %s добавлен, ID: %d\n\n"+
+			"Добавьте ему смену: /shift %d <дд.мм.гггг> <09:00-18:00>", staff.Name, id, id))
+
+	case "remove":
+		staff := b.getStaffArg(chatID, args, usage)
+		if staff == nil {
+			return
+		}
+		if err := b.storage.DeactivateStaff(staff.ID); err != nil {
+			b.sendMessage(chatID, "⚠️ Не удалось удалить мойщика")
+			return
+		}
+		b.sendMessage(chatID, fmt.Sprintf("✅ Мойщик %s убран из графика", staff.Name))
+
+	default:
+		b.sendMessage(chatID, usage)
+	}
+}
+
+func (b *CarWashBot) showStaff(chatID int64) {
+	staff, err := b.storage.ListStaff()
+	if err != nil {
+		b.sendMessage(chatID, "⚠️ Ошибка при получении списка мойщиков")
+		return
+	}
+	if len(staff) == 0 {
+		b.sendMessage(chatID, "👷 Мойщики не заведены, число моек ограничено только постами.\n\n"+
+			"Добавить мойщика: /staff add <telegram_id> <имя>")
+		return
+	}
+
+	var sb strings.Builder
+	sb.WriteString("👷 Мойщики:\n\n")
+	for _, st := range staff {
+		sb.WriteString(fmt.Sprintf("%d. %s", st.ID, st.Name))
+		if st.TelegramID != 0 {
+			sb.WriteString(fmt.Sprintf(" (TG %d)", st.TelegramID))
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\nСмены на день: /shifts <дд.мм.гггг>")
+	b.sendMessage(chatID, sb.String())
+}
+
+// handleShiftCommand добавляет и удаляет смены:
+// /shift <staff_id> <дд.мм.гггг> <09:00-18:00>, /shift remove <shift_id>
+func (b *CarWashBot) handleShiftCommand(chatID, userID int64, text string) {
+	if !b.isAdmin(userID) {
+		b.sendMessage(chatID, "❌ Команда доступна только администратору")
+		return
+	}
+
+	usage := "Использование:\n/shift <id мойщика> <дд.мм.гггг> <09:00-18:00>\n/shift remove <id смены>"
+	args := strings.Fields(text)[1:]
+
+	if len(args) == 2 && args[0] == "remove" {
+		id, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			b.sendMessage(chatID, usage)
+			return
+		}
+		if err := b.storage.DeleteShift(id); err != nil {
+			b.sendMessage(chatID, "⚠️ Не удалось удалить смену")
+			return
+		}
+		b.sendMessage(chatID, "✅ Смена удалена")
+		return
+	}
+
+	if len(args) != 3 {
+		b.sendMessage(chatID, usage)
+		return
+	}
+	staff := b.getStaffArg(chatID, args[0:1], usage)
+	if staff == nil {
+		return
+	}
+	if _, err := time.Parse("02.01.2006", args[1]); err != nil {
+		b.sendMessage(chatID, "❌ Неверный формат даты, нужно дд.мм.гггг")
+		return
+	}
+	bounds := strings.SplitN(args[2], "-", 2)
+	if len(bounds) != 2 {
+		b.sendMessage(chatID, usage)
+		return
+	}
+	start, errStart := time.Parse("15:04", bounds[0])
+	end, errEnd := time.Parse("15:04", bounds[1])
+	if errStart != nil || errEnd != nil || !end.After(start) {
+		b.sendMessage(chatID, "❌ Неверное время смены, пример: 09:00-18:00")
+		return
+	}
+
+	shift := models.Shift{
+		StaffID: staff.ID,
+		Date:    args[1],
+		Start:   start.Format("15:04"),
+		End:     end.Format("15:04"),
+	}
+	if _, err := b.storage.AddShift(shift); err != nil {
+		b.sendMessage(chatID, "⚠️ Не удалось добавить смену")
+		return
+	}
+	b.sendMessage(chatID, fmt.Sprintf("✅ Смена добавлена: %s, %s %s–%s",
+		staff.Name, shift.Date, shift.Start, shift.End))
+}
+
+// handleShiftsCommand показывает смены и загрузку мойщиков на день: /shifts [дд.мм.гггг]
+func (b *CarWashBot) handleShiftsCommand(chatID, userID int64, text string) {
+	if !b.isAdmin(userID) {
+		b.sendMessage(chatID, "❌ Команда доступна только администратору")
+		return
+	}
+
+	dateStr := time.Now().Format("02.01.2006")
+	if args := strings.Fields(text)[1:]; len(args) > 0 {
+		if _, err := time.Parse("02.01.2006", args[0]); err != nil {
+			b.sendMessage(chatID, "❌ Неверный формат даты, нужно дд.мм.гггг")
+			return
+		}
+		dateStr = args[0]
+	}
+
+	shifts, err := b.storage.GetShiftsByDate(dateStr)
+	if err != nil {
+		b.sendMessage(chatID, "⚠️ Ошибка при получении смен")
+		return
+	}
+	if len(shifts) == 0 {
+		b.sendMessage(chatID, fmt.Sprintf("📋 На %s смен нет", dateStr))
+		return
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("📋 Смены на %s:\n\n", dateStr))
+	for _, shift := range shifts {
+		name := fmt.Sprintf("#%d", shift.StaffID)
+		if staff, err := b.storage.GetStaff(shift.StaffID); err == nil && staff != nil {
+			name = staff.Name
+		}
+		bookings, err := b.storage.GetWasherBookings(shift.StaffID, dateStr)
+		if err != nil {
+			log.Printf("Ошибка получения очереди мойщика: %v", err)
+		}
+		sb.WriteString(fmt.Sprintf("[%d] %s: %s–%s, машин: %d\n", shift.ID, name, shift.Start, shift.End, len(bookings)))
+	}
+	b.sendMessage(chatID, sb.String())
+}
+
+// handleAssignCommand вручную назначает мойщика на запись: /assign <id записи> <id мойщика|0>
+func (b *CarWashBot) handleAssignCommand(chatID, userID int64, text string) {
+	if !b.isAdmin(userID) {
+		b.sendMessage(chatID, "❌ Команда доступна только администратору")
+		return
+	}
+
+	usage := "Использование:\n/assign <id записи> <id мойщика>\n/assign <id записи> 0 — снять назначение"
+	args := strings.Fields(text)[1:]
+	if len(args) != 2 {
+		b.sendMessage(chatID, usage)
+		return
+	}
+
+	booking, err := b.storage.GetBookingByID(args[0])
+	if err != nil {
+		b.sendMessage(chatID, "⚠️ Ошибка при получении записи")
+		return
+	}
+	if booking == nil {
+		b.sendMessage(chatID, "❌ Запись не найдена")
+		return
+	}
+
+	if args[1] == "0" {
+		if err := b.storage.SetBookingWasher(booking.ID, 0); err != nil {
+			b.sendMessage(chatID, "⚠️ Не удалось снять назначение")
+			return
+		}
+		b.sendMessage(chatID, "✅ Мойщик снят с записи")
+		return
+	}
+
+	staff := b.getStaffArg(chatID, args[1:], usage)
+	if staff == nil {
+		return
+	}
+	if err := b.storage.SetBookingWasher(booking.ID, staff.ID); err != nil {
+		b.sendMessage(chatID, "⚠️ Не удалось назначить мойщика")
+		return
+	}
+
+	text = fmt.Sprintf("✅ %s назначен на запись %s %s (%s %s)",
+		staff.Name, booking.Date, booking.Time, booking.CarModel, booking.CarNumber)
+	if !b.isWasherOnShift(staff.ID, *booking) {
+		text += "\n\n⚠️ В это время у мойщика нет смены"
+	}
+	b.sendMessage(chatID, text)
+
+	if staff.TelegramID != 0 {
+		b.sendMessage(staff.TelegramID, fmt.Sprintf("👷 Вам назначена машина:\n📅 %s в %s\n🚗 %s %s",
+			booking.Date, booking.Time, booking.CarModel, booking.CarNumber))
+	}
+}
+
+// isWasherOnShift проверяет, что запись целиком попадает в смену мойщика
+func (b *CarWashBot) isWasherOnShift(staffID int64, booking models.Booking) bool {
+	occupancy, err := bookingOccupancy(booking)
+	if err != nil {
+		return false
+	}
+	_, shifts, err := b.shiftWindows(booking.Date)
+	if err != nil {
+		return false
+	}
+	for _, shift := range shifts {
+		if shift.StaffID == staffID && !occupancy.Start.Before(shift.Start) && !occupancy.End.After(shift.End) {
+			return true
+		}
+	}
+	return false
+}
+
+// handleQueueCommand показывает мойщику его машины на день: /queue [дд.мм.гггг]
+func (b *CarWashBot) handleQueueCommand(chatID, userID int64, text string) {
+	staff, err := b.storage.GetStaffByTelegramID(userID)
+	if err != nil {
+		b.sendMessage(chatID, "⚠️ Ошибка при получении очереди")
+		return
+	}
+	if staff == nil {
+		b.sendMessage(chatID, "❌ Вы не числитесь среди мойщиков")
+		return
+	}
+
+	dateStr := time.Now().Format("02.01.2006")
+	if args := strings.Fields(text)[1:]; len(args) > 0 {
+		if _, err := time.Parse("02.01.2006", args[0]); err != nil {
+			b.sendMessage(chatID, "❌ Неверный формат даты, нужно дд.мм.гггг")
+			return
+		}
+		dateStr = args[0]
+	}
+
+	bookings, err := b.storage.GetWasherBookings(staff.ID, dateStr)
+	if err != nil {
+		b.sendMessage(chatID, "⚠️ Ошибка при получении очереди")
+		return
+	}
+	if len(bookings) == 0 {
+		b.sendMessage(chatID, fmt.Sprintf("👷 %s, на %s машин нет", staff.Name, dateStr))
+		return
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("👷 %s, ваша очередь на %s:\n\n", staff.Name, dateStr))
+	for _, booking := range bookings {
+		sb.WriteString(fmt.Sprintf("🕒 %s — пост %d\n🚗 %s %s", booking.Time, booking.Bay, booking.CarModel, booking.CarNumber))
+		if service := b.describeService(booking); service != "" {
+			sb.WriteString("\n🧽 " + service)
+		}
+		sb.WriteString("\n\n")
+	}
+	b.sendMessage(chatID, sb.String())
+}
+
+// getStaffArg находит работающего мойщика по ID из последнего аргумента команды
+func (b *CarWashBot) getStaffArg(chatID int64, args []string, usage string) *models.Staff {
+	if len(args) == 0 {
+		b.sendMessage(chatID, usage)
+		return nil
+	}
+	idArg := args[len(args)-1]
+	id, err := strconv.ParseInt(idArg, 10, 64)
+	if err != nil {
+		b.sendMessage(chatID, usage)
+		return nil
+	}
+
+	staff, err := b.storage.GetStaff(id)
+	if err != nil {
+		b.sendMessage(chatID, "⚠️ Ошибка при получении мойщика")
+		return nil
+	}
+	if staff == nil || !staff.Active {
+		b.sendMessage(chatID, "❌ Мойщик не найден")
+		return nil
+	}
+	return staff
+}
@@ -0,0 +1,235 @@
+package bot
+
+import (
+	"carwash-bot/internal/models"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Время "любое в этот день" в callback-данных листа ожидания
+const waitlistAnyTime = "any"
+
+// showSlotTaken предлагает встать в лист ожидания на занятое время
+func (b *CarWashBot) showSlotTaken(chatID int64, dateStr, timeStr string) {
+	msg := tgbotapi.NewMessage(chatID, b.t(chatID, "waitlist.taken", dateStr, timeStr))
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(b.t(chatID, "waitlist.wait_time", timeStr), "wl_join_"+dateStr+"_"+timeStr),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(b.t(chatID, "waitlist.wait_any"), "wl_join_"+dateStr+"_"+waitlistAnyTime),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(b.t(chatID, "waitlist.other_time"), "day_"+dateStr),
+			tgbotapi.NewInlineKeyboardButtonData(b.t(chatID, "menu.main"), "main_menu"),
+		),
+	)
+	b.sendMessageWithSave(chatID, msg)
+}
+
+// handleWaitlistCallback обрабатывает кнопки листа ожидания: wl_join_, wl_claim_, wl_decline_, wl_leave_
+func (b *CarWashBot) handleWaitlistCallback(chatID, userID int64, data string) {
+	switch {
+	case strings.HasPrefix(data, "wl_join_"):
+		parts := strings.SplitN(strings.TrimPrefix(data, "wl_join_"), "_", 2)
+		if len(parts) != 2 {
+			b.sendMessage(chatID, b.t(userID, "error.format"))
+			return
+		}
+		timeStr := parts[1]
+		if timeStr == waitlistAnyTime {
+			timeStr = ""
+		}
+		b.joinWaitlist(chatID, userID, parts[0], timeStr)
+
+	case strings.HasPrefix(data, "wl_claim_"):
+		b.claimWaitlistOffer(chatID, userID, strings.TrimPrefix(data, "wl_claim_"))
+
+	case strings.HasPrefix(data, "wl_decline_"), strings.HasPrefix(data, "wl_leave_"):
+		idStr := strings.TrimPrefix(strings.TrimPrefix(data, "wl_decline_"), "wl_leave_")
+		b.leaveWaitlist(chatID, userID, idStr)
+	}
+}
+
+func (b *CarWashBot) joinWaitlist(chatID, userID int64, dateStr, timeStr string) {
+	existing, err := b.storage.FindWaitlistEntry(userID, dateStr, timeStr)
+	if err != nil {
+		b.sendMessage(chatID, b.t(userID, "waitlist.join_error"))
+		return
+	}
+	if existing != nil {
+		b.sendMessage(chatID, b.t(userID, "waitlist.already"))
+		return
+	}
+
+	entry := models.WaitlistEntry{
+		UserID:  userID,
+		ChatID:  chatID,
+		Date:    dateStr,
+		Time:    timeStr,
+		Created: time.Now(),
+	}
+	entry.ID, err = b.storage.AddWaitlistEntry(entry)
+	if err != nil {
+		b.sendMessage(chatID, b.t(userID, "waitlist.join_error"))
+		return
+	}
+
+	position, err := b.storage.WaitlistPosition(entry)
+	if err != nil {
+		log.Printf("Ошибка получения позиции в листе ожидания: %v", err)
+	}
+
+	slotDesc := timeStr
+	if slotDesc == "" {
+		slotDesc = b.t(userID, "waitlist.any_time")
+	}
+
+	msg := tgbotapi.NewMessage(chatID, b.t(userID, "waitlist.joined",
+		dateStr, slotDesc, position, b.config.WaitlistClaimMinutes))
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(b.t(userID, "waitlist.leave"), fmt.Sprintf("wl_leave_%d", entry.ID)),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(b.t(chatID, "menu.main"), "main_menu"),
+		),
+	)
+	b.sendMessageWithSave(chatID, msg)
+}
+
+// claimWaitlistOffer забирает предложенное время и переводит пользователя к вводу данных авто
+func (b *CarWashBot) claimWaitlistOffer(chatID, userID int64, idStr string) {
+	entry := b.getOwnWaitlistEntry(chatID, userID, idStr)
+	if entry == nil {
+		return
+	}
+
+	if entry.Status != models.WaitlistOffered || !time.Now().Before(entry.OfferExpiresAt) {
+		b.sendMessage(chatID, b.t(userID, "waitlist.offer_gone"))
+		return
+	}
+
+	if !b.isSlotFreeFor(userID, entry.Date, entry.OfferedTime, "") {
+		b.storage.SetWaitlistStatus(entry.ID, models.WaitlistExpired)
+		b.sendMessage(chatID, b.t(userID, "waitlist.slot_taken"))
+		return
+	}
+
+	// Держим время, пока пользователь вводит данные авто
+	expiresAt := time.Now().Add(time.Duration(b.config.WaitlistClaimMinutes) * time.Minute)
+	if err := b.storage.ExtendWaitlistOffer(entry.ID, expiresAt); err != nil {
+		b.sendMessage(chatID, b.t(userID, "waitlist.hold_error"))
+		return
+	}
+
+	b.flow.Reset(userID)
+	state := models.UserState{
+		SelectedDate: entry.Date,
+		SelectedTime: entry.OfferedTime,
+	}
+	if !b.setState(chatID, userID, stateChoosingTime, state) {
+		return
+	}
+
+	b.sendMessage(chatID, b.t(userID, "waitlist.claimed", entry.Date, entry.OfferedTime, b.config.WaitlistClaimMinutes))
+	b.askCarClassOrInfo(chatID, userID, 0)
+}
+
+func (b *CarWashBot) leaveWaitlist(chatID, userID int64, idStr string) {
+	entry := b.getOwnWaitlistEntry(chatID, userID, idStr)
+	if entry == nil {
+		return
+	}
+
+	switch entry.Status {
+	case models.WaitlistWaiting:
+		b.storage.SetWaitlistStatus(entry.ID, models.WaitlistDeclined)
+		b.sendMessage(chatID, b.t(userID, "waitlist.left"))
+	case models.WaitlistOffered, models.WaitlistClaimed:
+		b.storage.SetWaitlistStatus(entry.ID, models.WaitlistDeclined)
+		b.sendMessage(chatID, b.t(userID, "waitlist.declined"))
+		// Передаём время следующему в очереди
+		b.offerFreedSlot(entry.Date, entry.OfferedTime)
+	default:
+		b.sendMessage(chatID, b.t(userID, "waitlist.inactive"))
+	}
+}
+
+func (b *CarWashBot) getOwnWaitlistEntry(chatID, userID int64, idStr string) *models.WaitlistEntry {
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		b.sendMessage(chatID, b.t(userID, "error.format"))
+		return nil
+	}
+
+	entry, err := b.storage.GetWaitlistEntry(id)
+	if err != nil {
+		b.sendMessage(chatID, b.t(userID, "waitlist.error"))
+		return nil
+	}
+	if entry == nil || entry.UserID != userID {
+		b.sendMessage(chatID, b.t(userID, "waitlist.not_found"))
+		return nil
+	}
+	return entry
+}
+
+// offerFreedSlot предлагает освободившееся время первому подходящему в листе ожидания
+func (b *CarWashBot) offerFreedSlot(dateStr, timeStr string) {
+	if !b.isSlotOpen(dateStr, timeStr, "") {
+		return
+	}
+
+	entry, err := b.storage.NextWaitlistEntry(dateStr, timeStr)
+	if err != nil {
+		log.Printf("Ошибка получения листа ожидания: %v", err)
+		return
+	}
+	if entry == nil {
+		return
+	}
+
+	claimWindow := time.Duration(b.config.WaitlistClaimMinutes) * time.Minute
+	if err := b.storage.OfferWaitlistSlot(entry.ID, timeStr, time.Now().Add(claimWindow)); err != nil {
+		log.Printf("Ошибка предложения времени из листа ожидания: %v", err)
+		return
+	}
+
+	msg := tgbotapi.NewMessage(entry.ChatID, b.t(entry.UserID, "waitlist.offer",
+		dateStr, timeStr, b.config.WaitlistClaimMinutes))
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(b.t(entry.UserID, "waitlist.claim", timeStr), fmt.Sprintf("wl_claim_%d", entry.ID)),
+			tgbotapi.NewInlineKeyboardButtonData(b.t(entry.UserID, "waitlist.decline"), fmt.Sprintf("wl_decline_%d", entry.ID)),
+		),
+	)
+	if _, err := b.botAPI.Send(msg); err != nil {
+		log.Printf("Ошибка отправки предложения из листа ожидания: %v", err)
+	}
+}
+
+// runWaitlistExpirer периодически снимает просроченные предложения
+// и передаёт время следующему в очереди
+func (b *CarWashBot) runWaitlistExpirer() {
+	ticker := time.NewTicker(30 * time.Second)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		expired, err := b.storage.ExpireWaitlistOffers(time.Now())
+		if err != nil {
+			log.Printf("Ошибка обработки листа ожидания: %v", err)
+			continue
+		}
+
+		for _, entry := range expired {
+			b.sendMessage(entry.ChatID, b.t(entry.UserID, "waitlist.offer_expired", entry.Date, entry.OfferedTime))
+			b.offerFreedSlot(entry.Date, entry.OfferedTime)
+		}
+	}
+}
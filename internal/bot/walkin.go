@@ -0,0 +1,342 @@
+package bot
+
+import (
+	"carwash-bot/internal/i18n"
+	"carwash-bot/internal/models"
+	"carwash-bot/internal/services"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Если мойка клиента из очереди затянулась, пост считается занятым ещё столько времени
+const walkInOverrun = 5 * time.Minute
+
+// walkInOccupancy возвращает посты, занятые клиентами живой очереди
+func (b *CarWashBot) walkInOccupancy() ([]services.Occupied, error) {
+	serving, err := b.storage.GetWalkInsByStatus(models.WalkInServing)
+	if err != nil {
+		return nil, err
+	}
+
+	duration, buffer := b.serviceTiming("")
+	now := time.Now()
+	var occupied []services.Occupied
+	for _, w := range serving {
+		end := w.StartedAt.Add(duration + services.BufferFor(b.bays(), w.Bay, buffer))
+		if end.Before(now) {
+			end = now.Add(walkInOverrun)
+		}
+		occupied = append(occupied, services.Occupied{Bay: w.Bay, Start: w.StartedAt, End: end})
+	}
+	return occupied, nil
+}
+
+// estimateWalkIns оценивает время начала мойки для каждого ожидающего в очереди
+func (b *CarWashBot) estimateWalkIns(count int) []time.Time {
+	now := time.Now()
+	occupied, _, err := b.dayOccupancy(now.Format("02.01.2006"), "")
+	if err != nil {
+		log.Printf("Ошибка получения занятости постов: %v", err)
+		return nil
+	}
+	duration, buffer := b.serviceTiming("")
+	return services.EstimateStarts(occupied, b.bays(), now, count, duration, buffer)
+}
+
+// joinWalkIn ставит клиента в живую очередь или показывает его текущее место
+func (b *CarWashBot) joinWalkIn(chatID, userID int64) {
+	if !b.config.WalkInQueue {
+		b.sendMessage(chatID, b.t(userID, "walkin.disabled", b.t(userID, "menu.book")))
+		return
+	}
+
+	existing, err := b.storage.FindActiveWalkIn(userID)
+	if err != nil {
+		b.sendMessage(chatID, b.t(userID, "walkin.join_error"))
+		return
+	}
+	if existing == nil {
+		walkIn := models.WalkIn{UserID: userID, ChatID: chatID, Created: time.Now()}
+		walkIn.ID, err = b.storage.AddWalkIn(walkIn)
+		if err != nil {
+			b.sendMessage(chatID, b.t(userID, "walkin.join_error"))
+			return
+		}
+		existing = &walkIn
+		b.notifyAdmins("🚶 Новый клиент в живой очереди. Управление: /walkins")
+	}
+
+	text, markup := b.walkInStatus(*existing)
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ReplyMarkup = markup
+	sent, err := b.botAPI.Send(msg)
+	if err != nil {
+		log.Printf("Ошибка отправки сообщения очереди: %v", err)
+		return
+	}
+	// Дальше позиция обновляется в этом сообщении
+	if err := b.storage.SetWalkInMessage(existing.ID, sent.MessageID); err != nil {
+		log.Printf("Ошибка сохранения сообщения очереди: %v", err)
+	}
+}
+
+// walkInStatus формирует сообщение клиенту о его месте в очереди
+func (b *CarWashBot) walkInStatus(walkIn models.WalkIn) (string, tgbotapi.InlineKeyboardMarkup) {
+	leaveRow := tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(b.t(walkIn.UserID, "walkin.leave"), fmt.Sprintf("wq_leave_%d", walkIn.ID)),
+	)
+
+	if walkIn.Status == models.WalkInServing {
+		return b.t(walkIn.UserID, "walkin.your_turn", walkIn.Bay),
+			tgbotapi.NewInlineKeyboardMarkup()
+	}
+
+	waiting, err := b.storage.GetWalkInsByStatus(models.WalkInWaiting)
+	if err != nil {
+		log.Printf("Ошибка получения очереди: %v", err)
+	}
+	position := 0
+	for i, w := range waiting {
+		if w.ID == walkIn.ID {
+			position = i + 1
+			break
+		}
+	}
+
+	text := b.t(walkIn.UserID, "walkin.position", position)
+	if starts := b.estimateWalkIns(position); position > 0 && len(starts) == position {
+		start := starts[position-1]
+		wait := time.Until(start).Round(time.Minute)
+		if wait <= 0 {
+			text += b.t(walkIn.UserID, "walkin.bay_free")
+		} else {
+			text += b.t(walkIn.UserID, "walkin.estimate", start.Format("15:04"), i18n.Duration(b.lang(walkIn.UserID), wait))
+		}
+	}
+	text += b.t(walkIn.UserID, "walkin.updated", time.Now().Format("15:04"))
+
+	return text, tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(b.t(walkIn.UserID, "walkin.refresh"), fmt.Sprintf("wq_refresh_%d", walkIn.ID)),
+		),
+		leaveRow,
+	)
+}
+
+// handleWalkInCallback обрабатывает кнопки клиента: wq_refresh_<id>, wq_leave_<id>
+func (b *CarWashBot) handleWalkInCallback(chatID, userID int64, data string) {
+	idStr := strings.TrimPrefix(strings.TrimPrefix(data, "wq_refresh_"), "wq_leave_")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		b.sendMessage(chatID, b.t(userID, "error.format"))
+		return
+	}
+	walkIn, err := b.storage.GetWalkIn(id)
+	if err != nil || walkIn == nil || walkIn.UserID != userID {
+		b.sendMessage(chatID, b.t(userID, "walkin.not_found"))
+		return
+	}
+
+	if strings.HasPrefix(data, "wq_leave_") && walkIn.Status == models.WalkInWaiting {
+		if err := b.storage.SetWalkInStatus(walkIn.ID, models.WalkInLeft); err != nil {
+			b.sendMessage(chatID, b.t(userID, "walkin.leave_error"))
+			return
+		}
+		walkIn.Status = models.WalkInLeft
+		b.editWalkInMessage(*walkIn, b.t(userID, "walkin.left"), tgbotapi.NewInlineKeyboardMarkup())
+		b.refreshWalkIns()
+		return
+	}
+
+	b.updateWalkInMessage(*walkIn)
+}
+
+// updateWalkInMessage обновляет сообщение клиента о месте в очереди
+func (b *CarWashBot) updateWalkInMessage(walkIn models.WalkIn) {
+	switch walkIn.Status {
+	case models.WalkInWaiting, models.WalkInServing:
+		text, markup := b.walkInStatus(walkIn)
+		b.editWalkInMessage(walkIn, text, markup)
+	default:
+		b.editWalkInMessage(walkIn, b.t(walkIn.UserID, "walkin.gone"), tgbotapi.NewInlineKeyboardMarkup())
+	}
+}
+
+func (b *CarWashBot) editWalkInMessage(walkIn models.WalkIn, text string, markup tgbotapi.InlineKeyboardMarkup) {
+	if walkIn.MessageID == 0 {
+		return
+	}
+	edit := tgbotapi.NewEditMessageText(walkIn.ChatID, walkIn.MessageID, text)
+	if len(markup.InlineKeyboard) > 0 {
+		edit.ReplyMarkup = &markup
+	}
+	// Telegram отвечает ошибкой, если текст не изменился - это не страшно
+	if _, err := b.botAPI.Send(edit); err != nil && !strings.Contains(err.Error(), "message is not modified") {
+		log.Printf("Ошибка обновления сообщения очереди: %v", err)
+	}
+}
+
+// refreshWalkIns обновляет позиции и ожидание у всех в очереди
+func (b *CarWashBot) refreshWalkIns() {
+	waiting, err := b.storage.GetWalkInsByStatus(models.WalkInWaiting)
+	if err != nil {
+		log.Printf("Ошибка получения очереди: %v", err)
+		return
+	}
+	for _, w := range waiting {
+		b.updateWalkInMessage(w)
+	}
+}
+
+// runWalkInUpdater периодически обновляет ожидание в сообщениях очереди
+func (b *CarWashBot) runWalkInUpdater() {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		b.refreshWalkIns()
+	}
+}
+
+// handleWalkInsCommand показывает админу живую очередь: /walkins
+func (b *CarWashBot) handleWalkInsCommand(chatID, userID int64) {
+	if !b.isAdmin(userID) {
+		b.sendMessage(chatID, "❌ Команда доступна только администратору")
+		return
+	}
+	b.showWalkInQueue(chatID, 0)
+}
+
+// showWalkInQueue выводит очередь для админа. Если messageID != 0, обновляет существующее сообщение
+func (b *CarWashBot) showWalkInQueue(chatID int64, messageID int) {
+	waiting, err := b.storage.GetWalkInsByStatus(models.WalkInWaiting)
+	if err != nil {
+		b.sendMessage(chatID, "⚠️ Ошибка при получении очереди")
+		return
+	}
+	serving, err := b.storage.GetWalkInsByStatus(models.WalkInServing)
+	if err != nil {
+		b.sendMessage(chatID, "⚠️ Ошибка при получении очереди")
+		return
+	}
+
+	var sb strings.Builder
+	sb.WriteString("🚶 Живая очередь\n\n")
+	var rows [][]tgbotapi.InlineKeyboardButton
+
+	if len(serving) > 0 {
+		sb.WriteString("На постах:\n")
+		for _, w := range serving {
+			sb.WriteString(fmt.Sprintf("🚗 Пост %d — с %s (ID %d)\n", w.Bay, w.StartedAt.Format("15:04"), w.UserID))
+			rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("✅ Пост %d освободился", w.Bay),
+					fmt.Sprintf("admin_wq_done:%d", w.ID)),
+			))
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(waiting) == 0 {
+		sb.WriteString("Очередь пуста")
+	} else {
+		starts := b.estimateWalkIns(len(waiting))
+		sb.WriteString("Ожидают:\n")
+		for i, w := range waiting {
+			sb.WriteString(fmt.Sprintf("%d. ID %d, с %s", i+1, w.UserID, w.Created.Format("15:04")))
+			if i < len(starts) {
+				sb.WriteString(", ≈ " + starts[i].Format("15:04"))
+			}
+			sb.WriteString("\n")
+		}
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("▶️ Вызвать следующего", "admin_wq_next"),
+		))
+	}
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("🔄 Обновить", "admin_wq_show"),
+	))
+	markup := tgbotapi.NewInlineKeyboardMarkup(rows...)
+
+	if messageID != 0 {
+		edit := tgbotapi.NewEditMessageTextAndMarkup(chatID, messageID, sb.String(), markup)
+		if _, err := b.botAPI.Send(edit); err != nil && !strings.Contains(err.Error(), "message is not modified") {
+			log.Printf("Ошибка обновления очереди: %v", err)
+		}
+		return
+	}
+	msg := tgbotapi.NewMessage(chatID, sb.String())
+	msg.ReplyMarkup = markup
+	b.sendMessageWithSave(chatID, msg)
+}
+
+// handleWalkInAdminCallback - кнопки админа: admin_wq_next, admin_wq_done:<id>, admin_wq_show
+func (b *CarWashBot) handleWalkInAdminCallback(query *tgbotapi.CallbackQuery) {
+	if !b.isAdmin(query.From.ID) {
+		b.answerCallback(query.ID, "❌ Действие доступно только администратору", true)
+		return
+	}
+
+	switch {
+	case query.Data == "admin_wq_next":
+		b.callNextWalkIn(query)
+
+	case strings.HasPrefix(query.Data, "admin_wq_done:"):
+		id, err := strconv.ParseInt(strings.TrimPrefix(query.Data, "admin_wq_done:"), 10, 64)
+		if err != nil {
+			b.answerCallback(query.ID, "⚠️ Ошибка формата данных", true)
+			return
+		}
+		if err := b.storage.SetWalkInStatus(id, models.WalkInDone); err != nil {
+			b.answerCallback(query.ID, "⚠️ Не удалось освободить пост", true)
+			return
+		}
+		b.answerCallback(query.ID, "✅ Пост свободен", false)
+		b.refreshWalkIns()
+
+	default:
+		b.answerCallback(query.ID, "", false)
+	}
+
+	b.showWalkInQueue(query.Message.Chat.ID, query.Message.MessageID)
+}
+
+// callNextWalkIn вызывает первого в очереди на свободный пост.
+// Пост должен быть свободен на всю мойку, чтобы не задеть ближайшую запись
+func (b *CarWashBot) callNextWalkIn(query *tgbotapi.CallbackQuery) {
+	waiting, err := b.storage.GetWalkInsByStatus(models.WalkInWaiting)
+	if err != nil || len(waiting) == 0 {
+		b.answerCallback(query.ID, "ℹ️ Очередь пуста", false)
+		return
+	}
+	next := waiting[0]
+
+	now := time.Now()
+	occupied, _, err := b.dayOccupancy(now.Format("02.01.2006"), "")
+	if err != nil {
+		b.answerCallback(query.ID, "⚠️ Ошибка получения занятости постов", true)
+		return
+	}
+	duration, buffer := b.serviceTiming("")
+	free := services.FreeBays(occupied, b.bays(), now, duration, buffer)
+	if len(free) == 0 {
+		b.answerCallback(query.ID, "⏳ Свободного поста нет: мешают текущие мойки или ближайшие записи", true)
+		return
+	}
+
+	if err := b.storage.StartWalkIn(next.ID, free[0], now); err != nil {
+		b.answerCallback(query.ID, "⚠️ Не удалось вызвать клиента", true)
+		return
+	}
+	next.Status, next.Bay, next.StartedAt = models.WalkInServing, free[0], now
+	b.answerCallback(query.ID, fmt.Sprintf("▶️ Клиент вызван на пост %d", free[0]), false)
+
+	b.updateWalkInMessage(next)
+	// Отдельное сообщение, чтобы клиент получил уведомление
+	b.sendMessage(next.ChatID, b.t(next.UserID, "walkin.called", free[0]))
+	b.refreshWalkIns()
+}
@@ -0,0 +1,209 @@
+// Package fsm - конечный автомат диалога с пользователем: именованные состояния,
+// разрешённые переходы, обработчики текста для каждого состояния и сброс по бездействию
+package fsm
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// State - именованное состояние диалога
+type State string
+
+// Idle - пользователь не находится ни в каком сценарии
+const Idle State = ""
+
+// ErrTransition - переход между состояниями не разрешён
+var ErrTransition = errors.New("переход не разрешён")
+
+// Handler обрабатывает текстовое сообщение пользователя в состоянии
+type Handler func(chatID, userID int64, text string)
+
+// StateDef описывает состояние
+type StateDef struct {
+	Handler Handler       // Обработчик текста. nil - текст обрабатывается как обычные команды
+	Timeout time.Duration // Через сколько бездействия состояние сбрасывается. 0 - без ограничения
+	Next    []State       // Куда можно перейти. Переход в Idle разрешён всегда
+}
+
+// Expired - сессия, сброшенная по бездействию
+type Expired struct {
+	UserID int64
+	ChatID int64
+	State  State
+}
+
+type session[T any] struct {
+	state   State
+	chatID  int64
+	data    T
+	updated time.Time
+}
+
+// Machine хранит текущее состояние и данные сценария каждого пользователя
+type Machine[T any] struct {
+	mu       sync.Mutex
+	states   map[State]StateDef
+	sessions map[int64]*session[T]
+	now      func() time.Time
+}
+
+func New[T any]() *Machine[T] {
+	return &Machine[T]{
+		states:   make(map[State]StateDef),
+		sessions: make(map[int64]*session[T]),
+		now:      time.Now,
+	}
+}
+
+// Define регистрирует состояние. Из Idle можно перейти в любое зарегистрированное состояние
+func (m *Machine[T]) Define(state State, def StateDef) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.states[state] = def
+}
+
+// State возвращает текущее состояние пользователя
+func (m *Machine[T]) State(userID int64) State {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if s, ok := m.sessions[userID]; ok {
+		return s.state
+	}
+	return Idle
+}
+
+// Data возвращает данные сценария пользователя
+func (m *Machine[T]) Data(userID int64) T {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if s, ok := m.sessions[userID]; ok {
+		return s.data
+	}
+	var zero T
+	return zero
+}
+
+// SetData обновляет данные сценария, не меняя состояние. Вне сценария данные
+// не сохраняются: такую сессию нечему сбросить по бездействию
+func (m *Machine[T]) SetData(userID int64, data T) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.sessions[userID]
+	if !ok || s.state == Idle {
+		return
+	}
+	s.data = data
+	s.updated = m.now()
+}
+
+// Transition переводит пользователя в состояние to с новыми данными сценария
+func (m *Machine[T]) Transition(userID, chatID int64, to State, data T) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if to != Idle {
+		if _, ok := m.states[to]; !ok {
+			return fmt.Errorf("%w: неизвестное состояние %q", ErrTransition, to)
+		}
+	}
+
+	// Переход в Idle завершает сценарий, как Reset
+	if to == Idle {
+		delete(m.sessions, userID)
+		return nil
+	}
+
+	s := m.session(userID)
+	if !m.allowed(s.state, to) {
+		return fmt.Errorf("%w: %q -> %q", ErrTransition, s.state, to)
+	}
+
+	s.state = to
+	s.chatID = chatID
+	s.data = data
+	s.updated = m.now()
+	return nil
+}
+
+func (m *Machine[T]) allowed(from, to State) bool {
+	if from == Idle || to == Idle {
+		return true
+	}
+	for _, next := range m.states[from].Next {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Reset завершает сценарий пользователя и стирает его данные
+func (m *Machine[T]) Reset(userID int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.sessions, userID)
+}
+
+// Touch отмечает активность пользователя, откладывая сброс по бездействию
+func (m *Machine[T]) Touch(userID int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if s, ok := m.sessions[userID]; ok {
+		s.updated = m.now()
+	}
+}
+
+// Handle передаёт текст обработчику текущего состояния.
+// Возвращает false, если у состояния нет обработчика текста
+func (m *Machine[T]) Handle(chatID, userID int64, text string) bool {
+	m.mu.Lock()
+	s, ok := m.sessions[userID]
+	var handler Handler
+	if ok {
+		handler = m.states[s.state].Handler
+		s.updated = m.now()
+	}
+	m.mu.Unlock()
+
+	// Обработчик вызывается без блокировки: он сам меняет состояние
+	if handler == nil {
+		return false
+	}
+	handler(chatID, userID, text)
+	return true
+}
+
+// Expire сбрасывает сессии, в которых пользователь бездействовал дольше таймаута состояния.
+// Сессии без сценария удаляются молча
+func (m *Machine[T]) Expire() []Expired {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	var expired []Expired
+	for userID, s := range m.sessions {
+		if s.state == Idle {
+			delete(m.sessions, userID)
+			continue
+		}
+		timeout := m.states[s.state].Timeout
+		if timeout <= 0 || now.Sub(s.updated) < timeout {
+			continue
+		}
+		expired = append(expired, Expired{UserID: userID, ChatID: s.chatID, State: s.state})
+		delete(m.sessions, userID)
+	}
+	return expired
+}
+
+func (m *Machine[T]) session(userID int64) *session[T] {
+	s, ok := m.sessions[userID]
+	if !ok {
+		s = &session[T]{}
+		m.sessions[userID] = s
+	}
+	return s
+}
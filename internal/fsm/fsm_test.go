@@ -0,0 +1,143 @@
+package fsm
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+const (
+	stateDay     State = "day"
+	stateTime    State = "time"
+	stateConfirm State = "confirm"
+)
+
+// testMachine - сценарий день -> время -> подтверждение с управляемыми часами
+func testMachine() (*Machine[string], *time.Time) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.Local)
+	m := New[string]()
+	m.now = func() time.Time { return now }
+	m.Define(stateDay, StateDef{Timeout: 10 * time.Minute, Next: []State{stateTime}})
+	m.Define(stateTime, StateDef{Timeout: 10 * time.Minute, Next: []State{stateDay, stateConfirm}})
+	m.Define(stateConfirm, StateDef{Next: []State{stateTime}})
+	return m, &now
+}
+
+func TestTransition(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    []State
+		wantErr bool
+	}{
+		{"из Idle в любое состояние", []State{stateConfirm}, false},
+		{"по разрешённым переходам", []State{stateDay, stateTime, stateConfirm}, false},
+		{"возврат назад", []State{stateDay, stateTime, stateDay}, false},
+		{"в Idle всегда", []State{stateConfirm, Idle}, false},
+		{"переход не разрешён", []State{stateDay, stateConfirm}, true},
+		{"неизвестное состояние", []State{"missing"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, _ := testMachine()
+			var err error
+			for _, to := range tt.path {
+				if err = m.Transition(1, 1, to, string(to)); err != nil {
+					break
+				}
+			}
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Transition() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrTransition) {
+				t.Errorf("Transition() error = %v, ожидалась ErrTransition", err)
+			}
+			if err == nil {
+				last := tt.path[len(tt.path)-1]
+				if got := m.State(1); got != last {
+					t.Errorf("State() = %q, ожидалось %q", got, last)
+				}
+			}
+		})
+	}
+}
+
+func TestReset(t *testing.T) {
+	m, _ := testMachine()
+	if err := m.Transition(1, 1, stateDay, "данные"); err != nil {
+		t.Fatal(err)
+	}
+	m.Reset(1)
+	if got := m.State(1); got != Idle {
+		t.Errorf("State() после Reset = %q, ожидалось Idle", got)
+	}
+	if got := m.Data(1); got != "" {
+		t.Errorf("Data() после Reset = %q, ожидалась пустая строка", got)
+	}
+	if len(m.sessions) != 0 {
+		t.Errorf("после Reset осталось сессий: %d", len(m.sessions))
+	}
+}
+
+func TestExpire(t *testing.T) {
+	m, now := testMachine()
+	for userID, state := range map[int64]State{1: stateDay, 2: stateTime, 3: stateConfirm} {
+		if err := m.Transition(userID, userID*10, state, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	*now = now.Add(9 * time.Minute)
+	m.Touch(2)
+	if expired := m.Expire(); len(expired) != 0 {
+		t.Fatalf("Expire() до таймаута = %v, ожидалось пусто", expired)
+	}
+
+	*now = now.Add(2 * time.Minute)
+	expired := m.Expire()
+	want := Expired{UserID: 1, ChatID: 10, State: stateDay}
+	if len(expired) != 1 || expired[0] != want {
+		t.Fatalf("Expire() = %v, ожидалось [%v]", expired, want)
+	}
+	if got := m.State(1); got != Idle {
+		t.Errorf("State() сброшенной сессии = %q, ожидалось Idle", got)
+	}
+	if got := m.State(2); got != stateTime {
+		t.Errorf("State() после Touch = %q, ожидалось %q", got, stateTime)
+	}
+
+	// У состояния без таймаута сессия не сбрасывается
+	*now = now.Add(24 * time.Hour)
+	expired = m.Expire()
+	if len(expired) != 1 || expired[0].UserID != 2 {
+		t.Fatalf("Expire() = %v, ожидался только пользователь 2", expired)
+	}
+	if got := m.State(3); got != stateConfirm {
+		t.Errorf("State() без таймаута = %q, ожидалось %q", got, stateConfirm)
+	}
+}
+
+// Данные вне сценария не создают сессию, которая никогда не сбросится
+func TestSetDataOutsideFlow(t *testing.T) {
+	m, _ := testMachine()
+	m.SetData(1, "данные")
+	if len(m.sessions) != 0 {
+		t.Fatalf("SetData вне сценария создал сессию")
+	}
+
+	if err := m.Transition(1, 1, stateDay, ""); err != nil {
+		t.Fatal(err)
+	}
+	m.SetData(1, "данные")
+	if got := m.Data(1); got != "данные" {
+		t.Errorf("Data() = %q, ожидалось %q", got, "данные")
+	}
+
+	if err := m.Transition(1, 1, Idle, "после"); err != nil {
+		t.Fatal(err)
+	}
+	m.SetData(1, "данные")
+	if len(m.sessions) != 0 {
+		t.Errorf("сессия осталась после перехода в Idle")
+	}
+}
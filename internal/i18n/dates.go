@@ -0,0 +1,83 @@
+package i18n
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+var weekdayNames = map[Lang][7]string{
+	RU: {"Воскресенье", "Понедельник", "Вторник", "Среда", "Четверг", "Пятница", "Суббота"},
+	EN: {"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+}
+
+// Короткие названия дней для шапки календаря, неделя начинается с понедельника
+var weekdayShortNames = map[Lang][7]string{
+	RU: {"Пн", "Вт", "Ср", "Чт", "Пт", "Сб", "Вс"},
+	EN: {"Mo", "Tu", "We", "Th", "Fr", "Sa", "Su"},
+}
+
+// Названия месяцев в именительном падеже: "Январь"
+var monthNames = map[Lang][12]string{
+	RU: {"Январь", "Февраль", "Март", "Апрель", "Май", "Июнь",
+		"Июль", "Август", "Сентябрь", "Октябрь", "Ноябрь", "Декабрь"},
+	EN: {"January", "February", "March", "April", "May", "June",
+		"July", "August", "September", "October", "November", "December"},
+}
+
+// Названия месяцев для даты с числом: "5 января"
+var monthGenitiveNames = map[Lang][12]string{
+	RU: {"января", "февраля", "марта", "апреля", "мая", "июня",
+		"июля", "августа", "сентября", "октября", "ноября", "декабря"},
+	EN: monthNames[EN],
+}
+
+// Weekday возвращает название дня недели: "Понедельник", "Monday"
+func Weekday(lang Lang, day time.Weekday) string {
+	return names(weekdayNames, lang)[day]
+}
+
+// WeekdaysShort возвращает короткие названия дней недели с понедельника
+func WeekdaysShort(lang Lang) [7]string {
+	return names(weekdayShortNames, lang)
+}
+
+// Month возвращает название месяца: "Январь", "January"
+func Month(lang Lang, month time.Month) string {
+	return names(monthNames, lang)[month-1]
+}
+
+// DayMonth форматирует число и месяц: "5 января", "January 5"
+func DayMonth(lang Lang, t time.Time) string {
+	month := names(monthGenitiveNames, lang)[t.Month()-1]
+	if lang == EN {
+		return fmt.Sprintf("%s %d", month, t.Day())
+	}
+	return fmt.Sprintf("%d %s", t.Day(), month)
+}
+
+// Duration форматирует длительность: "1 ч 30 мин", "1 h 30 min"
+func Duration(lang Lang, d time.Duration) string {
+	days := int(d / (24 * time.Hour))
+	hours := int(d % (24 * time.Hour) / time.Hour)
+	minutes := int(d % time.Hour / time.Minute)
+
+	var parts []string
+	if days > 0 {
+		parts = append(parts, T(lang, "duration.days", days))
+	}
+	if hours > 0 {
+		parts = append(parts, T(lang, "duration.hours", hours))
+	}
+	if minutes > 0 || len(parts) == 0 {
+		parts = append(parts, T(lang, "duration.minutes", minutes))
+	}
+	return strings.Join(parts, " ")
+}
+
+func names[T any](table map[Lang]T, lang Lang) T {
+	if v, ok := table[lang]; ok {
+		return v
+	}
+	return table[Default]
+}
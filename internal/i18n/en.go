@@ -0,0 +1,341 @@
+package i18n
+
+var en = map[string]string{
+	// Main menu
+	"menu.main":        "🏠 Main menu",
+	"menu.book":        "📝 Book a wash",
+	"menu.asap":        "⚡ Earliest time",
+	"menu.walkin":      "🚶 Walk-in queue",
+	"menu.schedule":    "🕒 Schedule",
+	"menu.my_bookings": "❌ My bookings",
+	"menu.cancel":      "❌ Cancel booking",
+	"menu.balance":     "⭐ My balance",
+	"menu.help":        "ℹ️ Help",
+
+	"welcome": "🚗 *Welcome to the car wash bot!* 🧼\n\n" +
+		"Choose an action:\n\n🌐 Язык / Language: /language",
+	"unknown_command": "I don't understand this command. Please use the menu buttons.",
+
+	"button.back":         "🔙 Back",
+	"button.back_to_menu": "🏠 Back to main menu",
+
+	// Language
+	"language.choose":  "🌐 Choose your language:",
+	"language.name":    "🇬🇧 English",
+	"language.changed": "✅ Interface language: English",
+
+	// Booking flow
+	"flow.cancelled": "❌ Action cancelled",
+	"flow.outdated":  "⚠️ This action is out of date, please start again from the menu",
+	"flow.expired":   "⌛ You haven't replied for a while, so the booking was cancelled. Please start again from the menu",
+	"flow.stale":     "❌ This choice is out of date, please start booking again",
+	"screen.stale":   "⌛ This menu is out of date, please use the latest message",
+
+	// Services and car classes
+	"service.choose":    "Choose a service:\n\nThe price depends on the day, time and car class.",
+	"service.from":      "%s — from %s",
+	"service.not_found": "❌ Service not found",
+	"class.choose":      "Choose your car class:",
+	"class.not_found":   "❌ Car class not found",
+
+	// Calendar
+	"calendar.header": "Choose a day:\n\n" +
+		"Crossed-out days are unavailable: the car wash is closed or fully booked.",
+	"calendar.closed":      "🚫 The car wash is closed on this day",
+	"calendar.full":        "🔴 This day is fully booked",
+	"calendar.range.one":   "📅 Booking is open %d day ahead",
+	"calendar.range.other": "📅 Booking is open %d days ahead",
+	"calendar.bad_month":   "❌ Invalid date format",
+
+	// Day selection
+	"day.bad_format":    "❌ Invalid date format",
+	"day.past":          "❌ You can't book a date in the past",
+	"day.horizon.one":   "❌ Booking is only available up to %d day ahead",
+	"day.horizon.other": "❌ Booking is only available up to %d days ahead",
+	"day.closed":        "❌ The car wash is closed on this day",
+	"day.too_late":      "❌ Booking for today is already closed",
+	"day.full":          "❌ This day is fully booked",
+
+	// Time selection
+	"time.header":        "Choose a time on %s, %s:",
+	"time.unavailable":   "🔴 %s (Unavailable)",
+	"time.free":          "🟢 %s (Free)",
+	"time.back_to_dates": "🔙 Back to dates",
+
+	// Car details
+	"car.prompt":    "Enter your car make and license plate",
+	"car.bad_plate": "❌ Couldn't recognize the license plate.",
+	"car.no_model":  "❌ Please enter the car make together with the plate.",
+	"car.example": "For example: Lada Vesta А123ВС77\n" +
+		"Taxi: Renault Logan АВ12377, trailer: MZSA АВ1234 77, foreign plate: Audi WOBZK295",
+
+	// Booking confirmation
+	"summary.text": "📝 Please check your booking:\n\n" +
+		"📅 Date: %s\n🕒 Time: %s\n🚗 Car: %s %s\n🧽 Service: %s\n💰 Price: %s",
+	"summary.confirm":      "✅ Confirm",
+	"summary.edit_time":    "✏️ Change time",
+	"summary.edit_car":     "✏️ Change car",
+	"summary.use_points":   "🎁 Free for %d points",
+	"summary.keep_points":  "↩️ Keep my points",
+	"summary.reward_price": "%s (%d points will be spent)",
+	"summary.promo_price":  "%s → %s (promo code %s)",
+	"summary.enter_promo":  "🏷 Enter promo code",
+	"summary.remove_promo": "❌ Remove promo code",
+
+	"booking.save_error": "⚠️ Failed to save the booking",
+	"booking.created": "✅ Your car wash is booked!\n\n" +
+		"📅 Date: %s\n🕒 Time: %s\n🚗 Car: %s %s\n🧽 Service: %s\n💰 Price: %s\n\n" +
+		"Thank you for choosing us!",
+	"booking.pending": "⏳ Your booking is waiting for administrator approval\n\n" +
+		"📅 Date: %s\n🕒 Time: %s\n🚗 Car: %s %s\n🧽 Service: %s\n💰 Price: %s\n\n" +
+		"We'll message you once it's approved.",
+
+	// Schedule
+	"schedule.title":    "📅 *Car wash schedule*\n\n",
+	"schedule.today":    "=== Today, %s ===\n",
+	"schedule.tomorrow": "=== Tomorrow, %s ===\n",
+	"schedule.day":      "=== %s, %s ===\n",
+	"schedule.empty":    "There are no bookings yet\n",
+	"schedule.error":    "⚠️ Failed to load the schedule",
+
+	// My bookings and cancellation
+	"bookings.error":            "⚠️ Failed to load your bookings",
+	"bookings.none":             "You have no active bookings.",
+	"bookings.title":            "📋 *Your bookings:*\n\n",
+	"bookings.pending":          "⏳ Waiting for administrator approval\n",
+	"bookings.awaiting_payment": "💳 Awaiting payment\n",
+	"bookings.cancel":           "❌ Cancel %s %s",
+	"bookings.reschedule":       "🔁 Reschedule",
+	"bookings.cancel_series":    "🔁 Cancel the whole series at %s",
+
+	"cancel.choose":    "Choose a booking to cancel:",
+	"cancel.error":     "❌ Failed to cancel the booking",
+	"cancel.not_found": "❌ Booking not found",
+	"cancel.not_yours": "❌ This is not your booking",
+	"cancel.failed":    "❌ Couldn't cancel the booking",
+	"cancel.done":      "✅ Booking cancelled:\n📅 %s\n🕒 %s\n🚗 %s %s",
+
+	// Reminders
+	"reminder.text": "⏰ Reminder: your car wash is %s\n\n" +
+		"📅 Date: %s\n🕒 Time: %s\n🚗 Car: %s %s",
+	"reminder.in_hours.one":     "in %d hour",
+	"reminder.in_hours.other":   "in %d hours",
+	"reminder.in_minutes.one":   "in %d minute",
+	"reminder.in_minutes.other": "in %d minutes",
+	"reminder.coming":           "✅ I'll be there",
+	"reminder.cancel":           "❌ Cancel",
+	"reminder.reschedule":       "🔁 Reschedule",
+	"reminder.confirmed":        "✅ Thank you, see you soon!",
+
+	// Feedback
+	"feedback.ask": "🧼 How was your car wash on %s at %s?\n🚗 %s %s\n\n" +
+		"Please rate it from 1 to 5 — it helps us improve.",
+	"feedback.rated":       "Car wash on %s at %s\nYour rating: %s",
+	"feedback.ask_comment": "💬 Anything to add? Send your comment in one message.",
+	"feedback.skip":        "⏭ No comment",
+	"feedback.thanks":      "🙏 Thank you for your feedback!",
+	"feedback.error":       "⚠️ Failed to save your feedback, please try again",
+
+	// Loyalty program
+	"loyalty.balance":      "⭐ Your balance: %d points",
+	"loyalty.earned":       "⭐ +1 point for your car wash! Balance: %d",
+	"loyalty.left.one":     "%d more point until a free wash",
+	"loyalty.left.other":   "%d more points until a free wash",
+	"loyalty.reward_ready": "🎁 You've earned a free wash! Choose it when confirming your booking.",
+	"loyalty.rules":        "You get 1 point for every paid car wash, a free wash costs %d points.",
+	"loyalty.history":      "Recent activity:",
+	"loyalty.adjusted":     "⭐ An administrator changed your balance: %+d. Balance: %d",
+	"loyalty.not_enough":   "❌ You no longer have enough points for a free wash",
+	"loyalty.disabled":     "The loyalty program is not available right now.",
+
+	"loyalty.reason.visit":         "car wash",
+	"loyalty.reason.visit_revoked": "visit reversed",
+	"loyalty.reason.redeem":        "free wash",
+	"loyalty.reason.refund":        "refund for cancellation",
+	"loyalty.reason.adjust":        "adjustment",
+
+	// Promo codes
+	"promo.prompt":        "🏷 Enter a promo code or send \"%s\" to continue without one",
+	"promo.saved":         "🏷 Promo code %s saved: %s off. It will be applied to your next booking.",
+	"promo.not_found":     "❌ This promo code doesn't exist",
+	"promo.not_yet":       "❌ This promo code is valid for later dates",
+	"promo.expired":       "❌ This promo code has expired",
+	"promo.used_up":       "❌ This promo code is no longer available",
+	"promo.user_limit":    "❌ You've already used this promo code",
+	"promo.wrong_service": "❌ This promo code doesn't apply to the selected service",
+	"promo.wrong_time":    "❌ This promo code doesn't apply at the selected time",
+	"promo.error":         "⚠️ Couldn't check the promo code, please try again later",
+
+	// Prepayment
+	"payment.title":           "Car wash booking prepayment",
+	"payment.description":     "Wash on %s at %s, %s %s",
+	"payment.hold":            "💳 The time is held for you for %d min. Pay the invoice above to confirm the booking.",
+	"payment.invoice_error":   "⚠️ Couldn't send the invoice. The booking will be released if it isn't paid.",
+	"payment.unavailable":     "The booking has been released or changed, it can't be paid",
+	"payment.received":        "✅ Payment received, thank you!",
+	"payment.late":            "⚠️ The payment arrived after the booking was released. The administrator will refund you; please book again.",
+	"payment.expired":         "⌛ Your booking on %s at %s was released: the prepayment didn't arrive in time",
+	"payment.refund":          "💳 Your booking on %s at %s was cancelled. The administrator will refund your prepayment of %s",
+	"payment.cancel_paid":     "💳 This booking is prepaid. To cancel it and get a refund, please contact the administrator",
+	"payment.reschedule_paid": "💳 This booking is prepaid. To reschedule it, please contact the administrator",
+
+	// Inline mode
+	"inline.day.today":          "today",
+	"inline.day.tomorrow":       "tomorrow",
+	"inline.day.after_tomorrow": "day after tomorrow",
+	"inline.day_title.one":      "🚗 %s: %d free slot",
+	"inline.day_title.other":    "🚗 %s: %d free slots",
+	"inline.day_text":           "🚗 Free car wash times, %s:\n%s\n\nTap a time to book it",
+	"inline.slot_text":          "🚗 The car wash is free on %s at %s",
+	"inline.slot_description":   "%s · tap to share",
+	"inline.book":               "📅 Book %s",
+	"inline.slot_unavailable":   "❌ This time can't be booked. Please choose another one",
+	"inline.none":               "No free time — open the bot",
+
+	// Common errors
+	"error.format": "❌ Invalid data format",
+
+	// Waitlist
+	"waitlist.taken":         "❌ %s %s is already taken!\n\nChoose another time or join the waitlist — if the booking is cancelled, we will offer it to you.",
+	"waitlist.wait_time":     "🔔 Wait for %s",
+	"waitlist.wait_any":      "🔔 Any time that day",
+	"waitlist.other_time":    "🕒 Choose another time",
+	"waitlist.join_error":    "⚠️ Failed to join the waitlist",
+	"waitlist.already":       "ℹ️ You are already on the waitlist for this time",
+	"waitlist.any_time":      "any time",
+	"waitlist.joined":        "🔔 You are on the waitlist for %s, %s\nYour place in line: %d\n\nIf the time frees up, we will message you. You will have %d min to confirm.",
+	"waitlist.leave":         "❌ Leave the waitlist",
+	"waitlist.offer_gone":    "⌛ This offer is no longer valid",
+	"waitlist.slot_taken":    "❌ Sorry, this time is already taken",
+	"waitlist.hold_error":    "⚠️ Failed to hold the time",
+	"waitlist.claimed":       "✅ %s %s is held for you for %d min.",
+	"waitlist.left":          "✅ You left the waitlist",
+	"waitlist.declined":      "✅ You declined the offered time",
+	"waitlist.inactive":      "ℹ️ This waitlist entry is no longer active",
+	"waitlist.error":         "⚠️ Failed to load the waitlist",
+	"waitlist.not_found":     "❌ Waitlist entry not found",
+	"waitlist.offer":         "🔔 A time has freed up: %s at %s!\n\nIt is held for you for %d min. Confirm it before it expires.",
+	"waitlist.claim":         "✅ Take %s",
+	"waitlist.decline":       "❌ Decline",
+	"waitlist.offer_expired": "⌛ The time to confirm %s %s has run out; it was passed to the next person in line",
+
+	// Durations
+	"duration.days":    "%d d",
+	"duration.hours":   "%d h",
+	"duration.minutes": "%d min",
+
+	// Booking rules
+	"policy.past_time":     "❌ You cannot book a time in the past",
+	"policy.min_notice":    "❌ Bookings must be made at least %s before the wash starts",
+	"policy.horizon.one":   "❌ Booking is open only %d day ahead",
+	"policy.horizon.other": "❌ Booking is open only %d days ahead",
+	"policy.started":       "❌ This wash has already started or passed",
+	"policy.change_cutoff": "❌ Bookings can be cancelled or rescheduled no later than %s before the start. If your plans have changed, please contact the administrator",
+	"policy.closed_time":   "❌ The car wash is closed at this time",
+	"policy.bad_date":      "❌ Invalid date format",
+
+	// Booking limits
+	"quota.cooldown":            "⏸ You have cancelled bookings too often. You can book again after %s",
+	"quota.max_active.one":      "❌ You already have %d active booking — that is the maximum. Cancel it or wait for the wash",
+	"quota.max_active.other":    "❌ You already have %d active bookings — that is the maximum. Cancel one of them or wait for the wash",
+	"quota.max_per_day.one":     "❌ You can book at most %d time per day. Please choose another day",
+	"quota.max_per_day.other":   "❌ You can book at most %d times per day. Please choose another day",
+	"quota.max_per_plate.one":   "❌ This car already has %d active booking — that is the maximum",
+	"quota.max_per_plate.other": "❌ This car already has %d active bookings — that is the maximum",
+
+	// No-shows
+	"noshow.blocked.one":   "🚫 Booking through the bot is unavailable: you missed %d booking. Please contact the administrator",
+	"noshow.blocked.other": "🚫 Booking through the bot is unavailable: you missed %d bookings. Please contact the administrator",
+	"noshow.horizon.one":   "❌ Because of missed bookings you can only book %d day ahead",
+	"noshow.horizon.other": "❌ Because of missed bookings you can only book %d days ahead",
+
+	// Phone
+	"phone.ask":        "📞 Share your phone number so the administrator can contact you if anything changes.\n\nTap the «%s» button below.",
+	"phone.send":       "📱 Share number",
+	"phone.can_skip":   "You can skip this step.",
+	"phone.skip":       "⏭ Skip",
+	"phone.skipped":    "OK, no phone number.",
+	"phone.not_yours":  "❌ This is not your contact. Share your own number with the «%s» button",
+	"phone.save_error": "⚠️ Failed to save the number, please try again",
+	"phone.saved":      "✅ Number saved",
+
+	// Nearest time
+	"nearest.none.one":      "😔 No free time in the next %d day",
+	"nearest.none.other":    "😔 No free time in the next %d days",
+	"nearest.title":         "⚡ Nearest free time:",
+	"nearest.title_service": "⚡ Nearest free time\n🧽 Service: %s",
+	"nearest.other_day":     "📅 Choose another day",
+
+	// Rescheduling
+	"reschedule.error":         "⚠️ Failed to load the booking",
+	"reschedule.unpaid":        "❌ An unpaid booking cannot be rescheduled. Cancel it and book again",
+	"reschedule.start":         "🔁 Rescheduling the booking %s %s (%s %s).\nChoose a new day:",
+	"reschedule.not_found":     "❌ The booking to reschedule was not found",
+	"reschedule.same_time":     "ℹ️ The booking is already at this time",
+	"reschedule.taken":         "❌ This time is already taken, please choose another",
+	"reschedule.just_taken":    "❌ This time was just taken, please choose another",
+	"reschedule.failed":        "⚠️ Failed to reschedule the booking",
+	"reschedule.done":          "✅ Booking rescheduled:\n📅 %s %s → %s %s\n🚗 %s %s",
+	"reschedule.price_changed": "\n💰 The price has changed: %s → %s",
+	"reschedule.by_admin":      "ℹ️ The administrator rescheduled your booking:\n📅 %s %s → %s %s\n🚗 %s %s",
+
+	// Walk-in queue
+	"walkin.disabled":    "ℹ️ The walk-in queue is not available right now. Book a convenient time with «%s»",
+	"walkin.join_error":  "⚠️ Failed to join the queue",
+	"walkin.leave":       "❌ Leave the queue",
+	"walkin.your_turn":   "🚗 It is your turn! Please drive up to bay %d",
+	"walkin.position":    "🚶 You are in the walk-in queue\n\n🔢 Your place: %d",
+	"walkin.bay_free":    "\n⏱ A bay is free, you will be called soon",
+	"walkin.estimate":    "\n⏱ At about %s (in ~%s)",
+	"walkin.updated":     "\n\n🕒 Updated at %s. Scheduled bookings are served at their time; the queue fills free bays between them.",
+	"walkin.refresh":     "🔄 Refresh",
+	"walkin.not_found":   "❌ Queue place not found",
+	"walkin.leave_error": "⚠️ Failed to leave the queue",
+	"walkin.left":        "✅ You left the queue",
+	"walkin.gone":        "ℹ️ You are no longer in the queue",
+	"walkin.called":      "🔔 It is your turn! Please drive up to bay %d",
+
+	// Recurring bookings
+	"recurring.offer":               "🔁 Wash your car regularly? You can book the same time ahead right away.",
+	"recurring.make":                "🔁 Make it recurring",
+	"recurring.already":             "ℹ️ This booking is already part of a recurring series",
+	"recurring.not_active":          "❌ Only a confirmed booking can be made recurring",
+	"recurring.needs_confirmation":  "❌ Recurring bookings are unavailable: your bookings are confirmed by the administrator",
+	"recurring.ask_frequency":       "How often should the %s booking repeat?",
+	"recurring.button.weekly":       "Every week",
+	"recurring.button.biweekly":     "Every two weeks",
+	"recurring.button.monthly":      "Every month",
+	"recurring.freq.weekly":         "every week",
+	"recurring.freq.biweekly":       "every two weeks",
+	"recurring.freq.monthly":        "every month",
+	"recurring.outdated":            "❌ This recurring booking setup is outdated, please start again",
+	"recurring.ask_count":           "How many times should the booking repeat (including this one)?",
+	"recurring.until_date":          "📅 Until a date",
+	"recurring.ask_until":           "Enter the date of the last booking as DD.MM.YYYY\nExample: 31.12.2026",
+	"recurring.bad_until":           "❌ Could not read the date. Enter it as DD.MM.YYYY, for example 31.12.2026",
+	"recurring.not_found":           "❌ The original booking was not found",
+	"recurring.until_too_early":     "❌ The end date must be after the first booking",
+	"recurring.no_dates":            "❌ There are no repetitions in this period",
+	"recurring.create_error":        "⚠️ Failed to create the recurring booking",
+	"recurring.conflict.closed":     "%s — the car wash is closed",
+	"recurring.conflict.taken":      "%s — the time is taken",
+	"recurring.conflict.horizon":    "%s — beyond your booking horizon",
+	"recurring.conflict.day_limit":  "%s — daily booking limit reached",
+	"recurring.conflict.prepayment": "%s — prepayment required, please book it separately",
+	"recurring.conflict.save_error": "%s — failed to save",
+	"recurring.created":             "🔁 Recurring booking created: %s at %s\n\n✅ Bookings (%d):\n",
+	"recurring.conflicts":           "\n⚠️ Could not book (%d):\n",
+	"recurring.cancel_hint":         "\nYou can cancel one booking or the whole series in «My bookings».",
+	"recurring.cancel_error":        "❌ Failed to cancel the series",
+	"recurring.cancelled":           "✅ Recurring booking cancelled. Bookings cancelled: %d",
+	"recurring.cancel_skipped":      "\n⚠️ Not cancelled: %d — they are too soon or already paid (the administrator will cancel them)",
+
+	// No-shows and admin confirmation
+	"noshow.notice":                 "🚫 You didn't show up for your car wash on %s at %s.\nIf your plans change, please cancel your booking in advance.",
+	"noshow.penalty_confirm":        "\n\nFrom now on your bookings will need to be confirmed by the administrator.",
+	"noshow.penalty_restrict.one":   "\n\nFrom now on you can only book %d day ahead.",
+	"noshow.penalty_restrict.other": "\n\nFrom now on you can only book %d days ahead.",
+	"noshow.penalty_block":          "\n\nBooking through the bot is closed for you. Please contact the administrator.",
+	"booking.confirmed_by_admin":    "✅ The administrator confirmed your booking:\n📅 %s at %s\n🚗 %s %s",
+	"booking.rejected_by_admin":     "❌ The administrator declined your booking:\n📅 %s at %s\n🚗 %s %s",
+}
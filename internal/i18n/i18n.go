@@ -0,0 +1,113 @@
+// Package i18n - переводы сообщений бота: каталоги строк по языкам,
+// правила множественного числа и названия дней недели и месяцев
+package i18n
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Lang - код языка интерфейса
+type Lang string
+
+const (
+	RU Lang = "ru"
+	EN Lang = "en"
+
+	// Default используется, если язык пользователя неизвестен
+	Default = RU
+)
+
+// Languages - поддерживаемые языки в порядке показа в настройках
+var Languages = []Lang{RU, EN}
+
+var catalogs = map[Lang]map[string]string{
+	RU: ru,
+	EN: en,
+}
+
+// Parse определяет язык по коду из Telegram ("en", "en-US", "ru").
+// Русский получают и пользователи близких языков, остальные - английский
+func Parse(code string) Lang {
+	code = strings.ToLower(code)
+	if i := strings.IndexAny(code, "-_"); i >= 0 {
+		code = code[:i]
+	}
+	switch code {
+	case "":
+		return Default
+	case "ru", "uk", "be", "kk":
+		return RU
+	}
+	return EN
+}
+
+// Supported проверяет, что для языка есть каталог
+func Supported(lang Lang) bool {
+	_, ok := catalogs[lang]
+	return ok
+}
+
+// T возвращает перевод строки key. Если аргументы переданы, строка форматируется через fmt.Sprintf.
+// Отсутствующий перевод берётся из языка по умолчанию, а если нет и его - возвращается сам ключ
+func T(lang Lang, key string, args ...any) string {
+	msg := lookup(lang, key)
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
+
+// Plural возвращает форму строки key для числа n: key.one, key.few, key.many или key.other.
+// Само число, если оно нужно в тексте, передаётся в args
+func Plural(lang Lang, key string, n int, args ...any) string {
+	form := key + "." + pluralForm(lang, n)
+	if _, ok := catalogs[lang][form]; !ok {
+		form = key + ".other"
+	}
+	return T(lang, form, args...)
+}
+
+// Matches проверяет, совпадает ли текст с переводом key на любом языке.
+// Нужен для кнопок меню: пользователь мог сменить язык, а старая клавиатура осталась
+func Matches(text, key string) bool {
+	for _, catalog := range catalogs {
+		if msg, ok := catalog[key]; ok && msg == text {
+			return true
+		}
+	}
+	return false
+}
+
+func lookup(lang Lang, key string) string {
+	if msg, ok := catalogs[lang][key]; ok {
+		return msg
+	}
+	if msg, ok := catalogs[Default][key]; ok {
+		return msg
+	}
+	return key
+}
+
+// pluralForm - правила выбора формы множественного числа (CLDR)
+func pluralForm(lang Lang, n int) string {
+	if n < 0 {
+		n = -n
+	}
+	switch lang {
+	case RU:
+		switch {
+		case n%10 == 1 && n%100 != 11:
+			return "one"
+		case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+			return "few"
+		default:
+			return "many"
+		}
+	default:
+		if n == 1 {
+			return "one"
+		}
+		return "other"
+	}
+}
@@ -0,0 +1,355 @@
+package i18n
+
+var ru = map[string]string{
+	// Главное меню
+	"menu.main":        "🏠 Главное меню",
+	"menu.book":        "📝 Записаться",
+	"menu.asap":        "⚡ Ближайшее время",
+	"menu.walkin":      "🚶 Живая очередь",
+	"menu.schedule":    "🕒 Расписание",
+	"menu.my_bookings": "❌ Мои записи",
+	"menu.cancel":      "❌ Отменить запись",
+	"menu.balance":     "⭐ Мой баланс",
+	"menu.help":        "ℹ️ Помощь",
+
+	"welcome": "🚗 *Добро пожаловать в бота автомойки!* 🧼\n\n" +
+		"Выберите действие:\n\n🌐 Язык / Language: /language",
+	"unknown_command": "Я не понимаю эту команду. Используйте кнопки меню.",
+
+	"button.back":         "🔙 Назад",
+	"button.back_to_menu": "🏠 В главное меню",
+
+	// Язык
+	"language.choose":  "🌐 Выберите язык:",
+	"language.name":    "🇷🇺 Русский",
+	"language.changed": "✅ Язык интерфейса: русский",
+
+	// Сценарий записи
+	"flow.cancelled": "❌ Действие отменено",
+	"flow.outdated":  "⚠️ Действие устарело, начните заново через меню",
+	"flow.expired":   "⌛ Вы долго не отвечали, начатое действие отменено. Начните заново через меню",
+	"flow.stale":     "❌ Выбор устарел, начните запись заново",
+	"screen.stale":   "⌛ Это меню устарело, воспользуйтесь последним сообщением",
+
+	// Услуги и классы авто
+	"service.choose":    "Выберите услугу:\n\nЦена зависит от дня, времени и класса автомобиля.",
+	"service.from":      "%s — от %s",
+	"service.not_found": "❌ Услуга не найдена",
+	"class.choose":      "Выберите класс автомобиля:",
+	"class.not_found":   "❌ Класс автомобиля не найден",
+
+	// Календарь
+	"calendar.header": "Выберите день для записи:\n\n" +
+		"Зачёркнутые дни недоступны: мойка не работает или всё время занято.",
+	"calendar.closed":     "🚫 В этот день мойка не работает",
+	"calendar.full":       "🔴 На этот день всё время занято",
+	"calendar.range.one":  "📅 Запись доступна на %d день вперёд",
+	"calendar.range.few":  "📅 Запись доступна на %d дня вперёд",
+	"calendar.range.many": "📅 Запись доступна на %d дней вперёд",
+	"calendar.bad_month":  "❌ Ошибка формата даты",
+
+	// Выбор дня
+	"day.bad_format":   "❌ Ошибка формата даты",
+	"day.past":         "❌ Нельзя записаться на прошедшую дату",
+	"day.horizon.one":  "❌ Запись доступна не более чем на %d день вперёд",
+	"day.horizon.few":  "❌ Запись доступна не более чем на %d дня вперёд",
+	"day.horizon.many": "❌ Запись доступна не более чем на %d дней вперёд",
+	"day.closed":       "❌ В этот день мойка не работает",
+	"day.too_late":     "❌ На сегодня время записи уже закончилось",
+	"day.full":         "❌ На этот день всё время занято",
+
+	// Выбор времени
+	"time.header":        "Выберите время на %s, %s:",
+	"time.unavailable":   "🔴 %s (Недоступно)",
+	"time.free":          "🟢 %s (Свободно)",
+	"time.back_to_dates": "🔙 Назад к выбору даты",
+
+	// Данные авто
+	"car.prompt":    "Введите марку и госномер машины",
+	"car.bad_plate": "❌ Не удалось распознать госномер.",
+	"car.no_model":  "❌ Укажите марку машины вместе с номером.",
+	"car.example": "Например: Lada Vesta А123ВС77\n" +
+		"Такси: Renault Logan АВ12377, прицеп: МЗСА АВ1234 77, иностранный номер: Audi WOBZK295",
+
+	// Подтверждение записи
+	"summary.text": "📝 Проверьте запись:\n\n" +
+		"📅 Дата: %s\n🕒 Время: %s\n🚗 Автомобиль: %s %s\n🧽 Услуга: %s\n💰 Стоимость: %s",
+	"summary.confirm":      "✅ Подтвердить",
+	"summary.edit_time":    "✏️ Изменить время",
+	"summary.edit_car":     "✏️ Изменить авто",
+	"summary.use_points":   "🎁 Бесплатно, баллов к списанию: %d",
+	"summary.keep_points":  "↩️ Не списывать баллы",
+	"summary.reward_price": "%s (баллов к списанию: %d)",
+	"summary.promo_price":  "%s → %s (промокод %s)",
+	"summary.enter_promo":  "🏷 Ввести промокод",
+	"summary.remove_promo": "❌ Убрать промокод",
+
+	"booking.save_error": "⚠️ Ошибка при сохранении записи",
+	"booking.created": "✅ Вы успешно записаны на мойку!\n\n" +
+		"📅 Дата: %s\n🕒 Время: %s\n🚗 Автомобиль: %s %s\n🧽 Услуга: %s\n💰 Стоимость: %s\n\n" +
+		"Спасибо за выбор нашей услуги!",
+	"booking.pending": "⏳ Запись создана и ждёт подтверждения администратора\n\n" +
+		"📅 Дата: %s\n🕒 Время: %s\n🚗 Автомобиль: %s %s\n🧽 Услуга: %s\n💰 Стоимость: %s\n\n" +
+		"Мы пришлём сообщение, когда администратор её подтвердит.",
+
+	// Расписание
+	"schedule.title":    "📅 *Расписание моек*\n\n",
+	"schedule.today":    "=== Сегодня, %s ===\n",
+	"schedule.tomorrow": "=== Завтра, %s ===\n",
+	"schedule.day":      "=== %s, %s ===\n",
+	"schedule.empty":    "На данный момент нет записей\n",
+	"schedule.error":    "⚠️ Ошибка при получении расписания",
+
+	// Мои записи и отмена
+	"bookings.error":            "⚠️ Ошибка при получении ваших записей",
+	"bookings.none":             "У вас нет активных записей.",
+	"bookings.title":            "📋 *Ваши записи:*\n\n",
+	"bookings.pending":          "⏳ Ждёт подтверждения администратора\n",
+	"bookings.awaiting_payment": "💳 Ждёт оплаты\n",
+	"bookings.cancel":           "❌ Отменить %s %s",
+	"bookings.reschedule":       "🔁 Перенести",
+	"bookings.cancel_series":    "🔁 Отменить всю серию на %s",
+
+	"cancel.choose":    "Выберите запись для отмены:",
+	"cancel.error":     "❌ Ошибка при отмене записи",
+	"cancel.not_found": "❌ Запись не найдена",
+	"cancel.not_yours": "❌ Это не ваша запись",
+	"cancel.failed":    "❌ Не удалось отменить запись",
+	"cancel.done":      "✅ Запись отменена:\n📅 %s\n🕒 %s\n🚗 %s %s",
+
+	// Напоминания
+	"reminder.text": "⏰ Напоминаем: мойка %s\n\n" +
+		"📅 Дата: %s\n🕒 Время: %s\n🚗 Автомобиль: %s %s",
+	"reminder.in_hours.one":    "через %d час",
+	"reminder.in_hours.few":    "через %d часа",
+	"reminder.in_hours.many":   "через %d часов",
+	"reminder.in_minutes.one":  "через %d минуту",
+	"reminder.in_minutes.few":  "через %d минуты",
+	"reminder.in_minutes.many": "через %d минут",
+	"reminder.coming":          "✅ Буду",
+	"reminder.cancel":          "❌ Отменить",
+	"reminder.reschedule":      "🔁 Перенести",
+	"reminder.confirmed":       "✅ Спасибо, ждём вас!",
+
+	// Оценка мойки
+	"feedback.ask": "🧼 Как прошла мойка %s в %s?\n🚗 %s %s\n\n" +
+		"Оцените её от 1 до 5 — это поможет нам стать лучше.",
+	"feedback.rated":       "Мойка %s в %s\nВаша оценка: %s",
+	"feedback.ask_comment": "💬 Хотите что-нибудь добавить? Напишите комментарий одним сообщением.",
+	"feedback.skip":        "⏭ Без комментария",
+	"feedback.thanks":      "🙏 Спасибо за отзыв!",
+	"feedback.error":       "⚠️ Не удалось сохранить отзыв, попробуйте ещё раз",
+
+	// Программа лояльности
+	"loyalty.balance":      "⭐ Ваш баланс баллов: %d",
+	"loyalty.earned":       "⭐ +1 балл за мойку! Баланс: %d",
+	"loyalty.left.one":     "До бесплатной мойки остался %d балл",
+	"loyalty.left.few":     "До бесплатной мойки осталось %d балла",
+	"loyalty.left.many":    "До бесплатной мойки осталось %d баллов",
+	"loyalty.reward_ready": "🎁 Вам доступна бесплатная мойка! Выберите её при подтверждении записи.",
+	"loyalty.rules":        "За каждую оплаченную мойку начисляется 1 балл, баллов на бесплатную мойку: %d.",
+	"loyalty.history":      "Последние операции:",
+	"loyalty.adjusted":     "⭐ Администратор изменил ваш баланс: %+d. Баланс: %d",
+	"loyalty.not_enough":   "❌ Баллов на бесплатную мойку уже не хватает",
+	"loyalty.disabled":     "Программа лояльности сейчас не действует.",
+
+	"loyalty.reason.visit":         "мойка",
+	"loyalty.reason.visit_revoked": "отмена начисления",
+	"loyalty.reason.redeem":        "бесплатная мойка",
+	"loyalty.reason.refund":        "возврат за отмену",
+	"loyalty.reason.adjust":        "корректировка",
+
+	// Промокоды
+	"promo.prompt":        "🏷 Введите промокод или отправьте «%s», чтобы продолжить без него",
+	"promo.saved":         "🏷 Промокод %s сохранён: скидка %s. Он применится при следующей записи.",
+	"promo.not_found":     "❌ Такого промокода нет",
+	"promo.not_yet":       "❌ Промокод действует на более поздние даты",
+	"promo.expired":       "❌ Срок действия промокода закончился",
+	"promo.used_up":       "❌ Промокод больше не действует: все использования исчерпаны",
+	"promo.user_limit":    "❌ Вы уже использовали этот промокод",
+	"promo.wrong_service": "❌ Промокод не действует на выбранную услугу",
+	"promo.wrong_time":    "❌ Промокод не действует в выбранное время",
+	"promo.error":         "⚠️ Не удалось проверить промокод, попробуйте позже",
+
+	// Предоплата
+	"payment.title":           "Предоплата записи на мойку",
+	"payment.description":     "Мойка %s в %s, %s %s",
+	"payment.hold":            "💳 Время удерживается за вами %d мин. Оплатите счёт выше, чтобы подтвердить запись.",
+	"payment.invoice_error":   "⚠️ Не удалось выставить счёт. Запись снимется, если её не оплатить.",
+	"payment.unavailable":     "Запись уже снята или изменилась, оплата невозможна",
+	"payment.received":        "✅ Оплата получена, спасибо!",
+	"payment.late":            "⚠️ Оплата пришла после снятия записи. Администратор вернёт деньги, запишитесь заново.",
+	"payment.expired":         "⌛ Запись на %s %s снята: предоплата не поступила вовремя",
+	"payment.refund":          "💳 Запись на %s %s снята. Предоплата %s будет возвращена администратором",
+	"payment.cancel_paid":     "💳 Запись оплачена. Чтобы отменить её и вернуть предоплату, свяжитесь с администратором",
+	"payment.reschedule_paid": "💳 Запись оплачена. Чтобы перенести её, свяжитесь с администратором",
+
+	// Inline-режим
+	"inline.day.today":          "сегодня",
+	"inline.day.tomorrow":       "завтра",
+	"inline.day.after_tomorrow": "послезавтра",
+	"inline.day_title.one":      "🚗 %s: %d свободное окно",
+	"inline.day_title.few":      "🚗 %s: %d свободных окна",
+	"inline.day_title.many":     "🚗 %s: %d свободных окон",
+	"inline.day_text":           "🚗 Свободное время на мойке, %s:\n%s\n\nНажмите на время, чтобы записаться",
+	"inline.slot_text":          "🚗 На мойке свободно: %s в %s",
+	"inline.slot_description":   "%s · нажмите, чтобы поделиться",
+	"inline.book":               "📅 Записаться на %s",
+	"inline.slot_unavailable":   "❌ На это время записаться нельзя. Выберите другое время",
+	"inline.none":               "Свободного времени нет — открыть бота",
+
+	// Общие ошибки
+	"error.format": "❌ Ошибка формата данных",
+
+	// Лист ожидания
+	"waitlist.taken":         "❌ Время %s %s уже занято!\n\nВыберите другое время или встаньте в лист ожидания — если запись отменят, мы предложим её вам.",
+	"waitlist.wait_time":     "🔔 Ждать %s",
+	"waitlist.wait_any":      "🔔 Любое время в этот день",
+	"waitlist.other_time":    "🕒 Выбрать другое время",
+	"waitlist.join_error":    "⚠️ Ошибка при записи в лист ожидания",
+	"waitlist.already":       "ℹ️ Вы уже в листе ожидания на это время",
+	"waitlist.any_time":      "любое время",
+	"waitlist.joined":        "🔔 Вы в листе ожидания на %s, %s\nВаше место в очереди: %d\n\nЕсли время освободится, мы пришлём сообщение. На подтверждение будет %d мин.",
+	"waitlist.leave":         "❌ Покинуть лист ожидания",
+	"waitlist.offer_gone":    "⌛ Предложение больше не действует",
+	"waitlist.slot_taken":    "❌ К сожалению, это время уже занято",
+	"waitlist.hold_error":    "⚠️ Ошибка при бронировании времени",
+	"waitlist.claimed":       "✅ Время %s %s закреплено за вами на %d мин.",
+	"waitlist.left":          "✅ Вы покинули лист ожидания",
+	"waitlist.declined":      "✅ Вы отказались от предложенного времени",
+	"waitlist.inactive":      "ℹ️ Эта запись в листе ожидания уже не активна",
+	"waitlist.error":         "⚠️ Ошибка при получении листа ожидания",
+	"waitlist.not_found":     "❌ Запись в листе ожидания не найдена",
+	"waitlist.offer":         "🔔 Освободилось время %s в %s!\n\nОно закреплено за вами на %d мин. Успейте подтвердить.",
+	"waitlist.claim":         "✅ Забрать %s",
+	"waitlist.decline":       "❌ Отказаться",
+	"waitlist.offer_expired": "⌛ Время на подтверждение записи %s %s истекло, оно передано следующему в очереди",
+
+	// Длительность
+	"duration.days":    "%d дн.",
+	"duration.hours":   "%d ч",
+	"duration.minutes": "%d мин",
+
+	// Правила записи
+	"policy.past_time":     "❌ Нельзя записаться на прошедшее время",
+	"policy.min_notice":    "❌ Записаться можно не позднее чем за %s до начала мойки",
+	"policy.horizon.one":   "❌ Запись открыта только на %d день вперёд",
+	"policy.horizon.few":   "❌ Запись открыта только на %d дня вперёд",
+	"policy.horizon.many":  "❌ Запись открыта только на %d дней вперёд",
+	"policy.started":       "❌ Эта мойка уже началась или прошла",
+	"policy.change_cutoff": "❌ Отменить или перенести запись можно не позднее чем за %s до начала. Если планы изменились, свяжитесь с администратором",
+	"policy.closed_time":   "❌ В это время мойка не работает",
+	"policy.bad_date":      "❌ Ошибка формата даты",
+
+	// Лимиты записей
+	"quota.cooldown":           "⏸ Вы слишком часто отменяли записи. Записаться снова можно после %s",
+	"quota.max_active.one":     "❌ У вас уже %d активная запись — это максимум. Отмените её или дождитесь мойки",
+	"quota.max_active.few":     "❌ У вас уже %d активные записи — это максимум. Отмените одну из них или дождитесь мойки",
+	"quota.max_active.many":    "❌ У вас уже %d активных записей — это максимум. Отмените одну из них или дождитесь мойки",
+	"quota.max_per_day.one":    "❌ На один день можно записаться не более %d раза. Выберите другой день",
+	"quota.max_per_day.few":    "❌ На один день можно записаться не более %d раз. Выберите другой день",
+	"quota.max_per_day.many":   "❌ На один день можно записаться не более %d раз. Выберите другой день",
+	"quota.max_per_plate.one":  "❌ На эту машину уже есть %d активная запись — это максимум",
+	"quota.max_per_plate.few":  "❌ На эту машину уже есть %d активные записи — это максимум",
+	"quota.max_per_plate.many": "❌ На эту машину уже есть %d активных записей — это максимум",
+
+	// Неявки
+	"noshow.blocked.one":  "🚫 Запись через бота недоступна: вы %d раз не приехали на мойку. Свяжитесь с администратором",
+	"noshow.blocked.few":  "🚫 Запись через бота недоступна: вы %d раза не приехали на мойку. Свяжитесь с администратором",
+	"noshow.blocked.many": "🚫 Запись через бота недоступна: вы %d раз не приехали на мойку. Свяжитесь с администратором",
+	"noshow.horizon.one":  "❌ Из-за пропущенных записей вам доступна запись только на %d день вперёд",
+	"noshow.horizon.few":  "❌ Из-за пропущенных записей вам доступна запись только на %d дня вперёд",
+	"noshow.horizon.many": "❌ Из-за пропущенных записей вам доступна запись только на %d дней вперёд",
+
+	// Телефон
+	"phone.ask":        "📞 Оставьте номер телефона, чтобы администратор мог связаться с вами, если что-то изменится.\n\nНажмите кнопку «%s» ниже.",
+	"phone.send":       "📱 Отправить номер",
+	"phone.can_skip":   "Этот шаг можно пропустить.",
+	"phone.skip":       "⏭ Пропустить",
+	"phone.skipped":    "Хорошо, без телефона.",
+	"phone.not_yours":  "❌ Это не ваш контакт. Отправьте свой номер кнопкой «%s»",
+	"phone.save_error": "⚠️ Не удалось сохранить номер, попробуйте ещё раз",
+	"phone.saved":      "✅ Номер сохранён",
+
+	// Ближайшее время
+	"nearest.none.one":      "😔 В ближайший %d день свободного времени нет",
+	"nearest.none.few":      "😔 В ближайшие %d дня свободного времени нет",
+	"nearest.none.many":     "😔 В ближайшие %d дней свободного времени нет",
+	"nearest.title":         "⚡ Ближайшее свободное время:",
+	"nearest.title_service": "⚡ Ближайшее свободное время\n🧽 Услуга: %s",
+	"nearest.other_day":     "📅 Выбрать другой день",
+
+	// Перенос записи
+	"reschedule.error":         "⚠️ Ошибка при получении записи",
+	"reschedule.unpaid":        "❌ Неоплаченную запись нельзя перенести. Отмените её и запишитесь заново",
+	"reschedule.start":         "🔁 Перенос записи %s %s (%s %s).\nВыберите новый день:",
+	"reschedule.not_found":     "❌ Запись для переноса не найдена",
+	"reschedule.same_time":     "ℹ️ Запись уже стоит на это время",
+	"reschedule.taken":         "❌ Это время уже занято, выберите другое",
+	"reschedule.just_taken":    "❌ Это время только что заняли, выберите другое",
+	"reschedule.failed":        "⚠️ Не удалось перенести запись",
+	"reschedule.done":          "✅ Запись перенесена:\n📅 %s %s → %s %s\n🚗 %s %s",
+	"reschedule.price_changed": "\n💰 Стоимость изменилась: %s → %s",
+	"reschedule.by_admin":      "ℹ️ Администратор перенёс вашу запись:\n📅 %s %s → %s %s\n🚗 %s %s",
+
+	// Живая очередь
+	"walkin.disabled":    "ℹ️ Живая очередь сейчас не работает. Запишитесь на удобное время через «%s»",
+	"walkin.join_error":  "⚠️ Ошибка при постановке в очередь",
+	"walkin.leave":       "❌ Покинуть очередь",
+	"walkin.your_turn":   "🚗 Ваша очередь! Подъезжайте к посту %d",
+	"walkin.position":    "🚶 Вы в живой очереди\n\n🔢 Ваше место: %d",
+	"walkin.bay_free":    "\n⏱ Пост свободен, скоро вас позовут",
+	"walkin.estimate":    "\n⏱ Примерно в %s (через ~%s)",
+	"walkin.updated":     "\n\n🕒 Обновлено в %s. Записи по времени обслуживаются в свой час, очередь занимает свободные посты между ними.",
+	"walkin.refresh":     "🔄 Обновить",
+	"walkin.not_found":   "❌ Место в очереди не найдено",
+	"walkin.leave_error": "⚠️ Не удалось покинуть очередь",
+	"walkin.left":        "✅ Вы покинули очередь",
+	"walkin.gone":        "ℹ️ Вы больше не в очереди",
+	"walkin.called":      "🔔 Ваша очередь! Подъезжайте к посту %d",
+
+	// Регулярная запись
+	"recurring.offer":               "🔁 Моете машину регулярно? Можно сразу записаться на это же время наперёд.",
+	"recurring.make":                "🔁 Сделать запись регулярной",
+	"recurring.already":             "ℹ️ Эта запись уже входит в регулярную серию",
+	"recurring.not_active":          "❌ Регулярной можно сделать только подтверждённую запись",
+	"recurring.needs_confirmation":  "❌ Регулярная запись недоступна: ваши записи подтверждает администратор",
+	"recurring.ask_frequency":       "Как часто повторять запись на %s?",
+	"recurring.button.weekly":       "Каждую неделю",
+	"recurring.button.biweekly":     "Раз в две недели",
+	"recurring.button.monthly":      "Каждый месяц",
+	"recurring.freq.weekly":         "каждую неделю",
+	"recurring.freq.biweekly":       "раз в две недели",
+	"recurring.freq.monthly":        "каждый месяц",
+	"recurring.outdated":            "❌ Настройка регулярной записи устарела, начните заново",
+	"recurring.ask_count":           "Сколько раз повторить запись (включая текущую)?",
+	"recurring.until_date":          "📅 До определённой даты",
+	"recurring.ask_until":           "Введите дату последней записи в формате ДД.ММ.ГГГГ\nПример: 31.12.2026",
+	"recurring.bad_until":           "❌ Не удалось распознать дату. Введите её в формате ДД.ММ.ГГГГ, например 31.12.2026",
+	"recurring.not_found":           "❌ Исходная запись не найдена",
+	"recurring.until_too_early":     "❌ Дата окончания должна быть позже первой записи",
+	"recurring.no_dates":            "❌ В указанный период нет ни одного повторения",
+	"recurring.create_error":        "⚠️ Ошибка при создании регулярной записи",
+	"recurring.conflict.closed":     "%s — мойка не работает",
+	"recurring.conflict.taken":      "%s — время занято",
+	"recurring.conflict.horizon":    "%s — за пределами доступного горизонта записи",
+	"recurring.conflict.day_limit":  "%s — превышен лимит записей на день",
+	"recurring.conflict.prepayment": "%s — нужна предоплата, запишитесь отдельно",
+	"recurring.conflict.save_error": "%s — ошибка сохранения",
+	"recurring.created":             "🔁 Регулярная запись создана: %s в %s\n\n✅ Записи (%d):\n",
+	"recurring.conflicts":           "\n⚠️ Не удалось записать (%d):\n",
+	"recurring.cancel_hint":         "\nОтменить одну запись или всю серию можно в разделе «Мои записи».",
+	"recurring.cancel_error":        "❌ Не удалось отменить серию",
+	"recurring.cancelled":           "✅ Регулярная запись отменена. Отменено записей: %d",
+	"recurring.cancel_skipped":      "\n⚠️ Не отменено: %d — до них осталось слишком мало времени или они оплачены (их отменит администратор)",
+
+	// Неявки и подтверждение записи администратором
+	"noshow.notice":                "🚫 Вы не приехали на мойку %s в %s.\nЕсли планы меняются, пожалуйста, отменяйте запись заранее.",
+	"noshow.penalty_confirm":       "\n\nТеперь ваши записи будут подтверждаться администратором.",
+	"noshow.penalty_restrict.one":  "\n\nТеперь запись доступна только на %d день вперёд.",
+	"noshow.penalty_restrict.few":  "\n\nТеперь запись доступна только на %d дня вперёд.",
+	"noshow.penalty_restrict.many": "\n\nТеперь запись доступна только на %d дней вперёд.",
+	"noshow.penalty_block":         "\n\nЗапись через бота для вас закрыта. Свяжитесь с администратором.",
+	"booking.confirmed_by_admin":   "✅ Администратор подтвердил вашу запись:\n📅 %s в %s\n🚗 %s %s",
+	"booking.rejected_by_admin":    "❌ Администратор отклонил вашу запись:\n📅 %s в %s\n🚗 %s %s",
+}
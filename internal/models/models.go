@@ -10,15 +10,258 @@ type Booking struct {
 	CarNumber string    `json:"car_number"`
 	UserID    int64     `json:"user_id"`
 	Created   time.Time `json:"created_at"`
+	SeriesID  int64     `json:"series_id"` // 0, если запись не входит в регулярную серию
+	Service   string    `json:"service"`   // Код услуги из прайс-листа
+	CarClass  string    `json:"car_class"` // Код класса авто из прайс-листа
+	Price     int       `json:"price"`     // Цена на момент записи
+	Duration  int       `json:"duration"`  // Длительность мойки в минутах
+	Buffer    int       `json:"buffer"`    // Уборка поста после мойки в минутах
+	Bay       int       `json:"bay"`       // Номер поста
+	WasherID  int64     `json:"washer_id"` // 0, если мойщик не назначен
+	Status    string    `json:"status"`    // BookingActive, BookingPending, BookingNoShow, BookingCompleted, BookingAwaitingPayment
+
+	LoyaltyPoints int    `json:"loyalty_points"` // Сколько баллов списано за бесплатную мойку, 0 - обычная запись
+	PromoCode     string `json:"promo_code"`     // Применённый промокод
+	Discount      int    `json:"discount"`       // Скидка по промокоду, Price указан уже с её учётом
+}
+
+// Статусы записи
+const (
+	BookingActive          = "active"
+	BookingPending         = "pending" // Ждёт подтверждения администратора
+	BookingNoShow          = "no_show"
+	BookingCompleted       = "completed"
+	BookingAwaitingPayment = "awaiting_payment" // Время удерживается до оплаты счёта
+)
+
+// WalkIn - клиент в живой очереди без записи
+type WalkIn struct {
+	ID        int64
+	UserID    int64
+	ChatID    int64
+	MessageID int // Сообщение с позицией в очереди, обновляется на месте
+	Status    string
+	Bay       int // Пост, на который вызван клиент
+	Created   time.Time
+	StartedAt time.Time
+}
+
+// Статусы живой очереди
+const (
+	WalkInWaiting = "waiting"
+	WalkInServing = "serving"
+	WalkInDone    = "done"
+	WalkInLeft    = "left"
+)
+
+// Job - отложенная задача по записи, например напоминание.
+// Хранится в базе, поэтому переживает перезапуск бота
+type Job struct {
+	ID            int64
+	Kind          string
+	BookingID     string
+	Slot          string // Дата и время записи на момент планирования, "02.01.2006 15:04"
+	OffsetMinutes int    // Для напоминания - за сколько минут до начала, для отзыва - через сколько после окончания
+	RunAt         time.Time
+	Status        string
+	Created       time.Time
+}
+
+// Виды задач
+const (
+	JobReminder      = "reminder"
+	JobFeedback      = "feedback"       // Просьба оценить мойку
+	JobPaymentExpiry = "payment_expiry" // Снятие неоплаченной записи
+)
+
+// Статусы задач
+const (
+	JobPending = "pending" // Ждёт времени запуска
+	JobRunning = "running" // Забрана на выполнение
+	JobDone    = "done"
+	JobSkipped = "skipped" // Запись отменена, перенесена или уже началась
+)
+
+// Payment - предоплата записи через Telegram Payments
+type Payment struct {
+	ID               int64
+	BookingID        string
+	UserID           int64
+	Amount           int // В копейках (минимальных единицах валюты)
+	Currency         string
+	TelegramChargeID string
+	ProviderChargeID string
+	Created          time.Time
+}
+
+// Feedback - оценка мойки клиентом
+type Feedback struct {
+	BookingID string
+	UserID    int64
+	WasherID  int64 // Мойщик записи, 0 - не назначен
+	Rating    int   // От 1 до 5
+	Comment   string
+	Created   time.Time
+	Updated   time.Time
+}
+
+// LoyaltyEntry - операция с баллами клиента. Баланс - сумма всех операций
+type LoyaltyEntry struct {
+	ID        int64
+	UserID    int64
+	BookingID string // Пусто для ручных корректировок
+	Delta     int
+	Reason    string
+	Comment   string
+	ActorID   int64 // Кто совершил операцию (клиент, админ или 0 - бот)
+	Created   time.Time
+}
+
+// Причины операций с баллами
+const (
+	LoyaltyVisit        = "visit"         // Начисление за мойку
+	LoyaltyVisitRevoked = "visit_revoked" // Мойку отметили неявкой после начисления
+	LoyaltyRedeem       = "redeem"        // Списание за бесплатную мойку
+	LoyaltyRefund       = "refund"        // Возврат баллов за отменённую бесплатную мойку
+	LoyaltyAdjust       = "adjust"        // Корректировка администратором
+)
+
+// PromoCode - промокод на скидку. Нулевые ограничения не действуют
+type PromoCode struct {
+	Code       string   // В верхнем регистре: "WINTER20"
+	Percent    int      // Скидка в процентах
+	Amount     int      // Фиксированная скидка, если Percent = 0
+	ValidFrom  string   // Первый день мойки по промокоду, "02.01.2006"
+	ValidUntil string   // Последний день мойки по промокоду
+	MaxUses    int      // Сколько раз промокод можно использовать всего
+	MaxPerUser int      // Сколько раз его может использовать один клиент
+	Services   []string // Коды услуг, на которые действует скидка
+	TimeFrom   string   // Начало мойки не раньше, "10:00"
+	TimeTo     string   // Начало мойки раньше, "16:00"
+	Active     bool
+	Created    time.Time
+}
+
+// Customer - профиль клиента
+type Customer struct {
+	UserID    int64
+	Phone     string // В международном формате, "+79991234567"
+	Language  string // Язык, выбранный в настройках. Пусто - язык из Telegram
+	PromoCode string // Промокод из ссылки, применится при следующей записи
+	Updated   time.Time
+}
+
+// Staff - мойщик
+type Staff struct {
+	ID         int64
+	TelegramID int64 // Для просмотра своей очереди через бота
+	Name       string
+	Active     bool
+	Created    time.Time
+}
+
+// Shift - смена мойщика на день
+type Shift struct {
+	ID      int64
+	StaffID int64
+	Date    string // Формат: "02.01.2006"
+	Start   string // Формат: "15:04"
+	End     string // Формат: "15:04"
+}
+
+// BookingEvent - событие в истории записи
+type BookingEvent struct {
+	ID        int64
+	BookingID string
+	UserID    int64 // Владелец записи
+	Action    string
+	Details   string
+	ActorID   int64 // Кто совершил действие (пользователь или админ)
+	Created   time.Time
+}
+
+// Действия в истории записи
+const (
+	EventCreated     = "created"
+	EventRescheduled = "rescheduled"
+	EventCancelled   = "cancelled"
+	EventConfirmed   = "confirmed"
+	EventNoShow      = "no_show"
+	EventCompleted   = "completed"
+
+	EventPaid      = "paid"       // Клиент внёс предоплату
+	EventRefundDue = "refund_due" // Оплаченную запись сняли, предоплату нужно вернуть
+
+	// Клиент подтвердил по напоминанию, что приедет
+	EventVisitConfirmed = "visit_confirmed"
+
+	// Отмена записи вместе со всей регулярной серией. Считается отдельно,
+	// чтобы отмена серии не выглядела как множество отдельных отмен
+	EventSeriesCancelled = "series_cancelled"
+)
+
+// BookingSeries - регулярная запись, из которой созданы отдельные записи Booking
+type BookingSeries struct {
+	ID        int64
+	UserID    int64
+	Frequency string // weekly, biweekly, monthly
+	StartDate string
+	Time      string
+	UntilDate string // Пусто, если серия ограничена количеством
+	Count     int    // 0, если серия ограничена датой
+	CarModel  string
+	CarNumber string
+	Created   time.Time
 }
 
+// UserState - данные сценария записи. Текущий шаг сценария хранит конечный автомат в internal/fsm
 type UserState struct {
-	AwaitingDay     bool
-	AwaitingTime    bool
-	AwaitingCarInfo bool
 	SelectedDate    string
 	SelectedTime    string
+	SelectedService string
+	SelectedClass   string
+	CarModel        string
+	CarNumber       string
+	PhoneSkipped    bool // Клиент отказался оставить телефон
+	UseReward       bool // Клиент списывает баллы за бесплатную мойку
+	PromoCode       string
+	PromoOffered    bool // Промокод из ссылки уже подставлялся в эту запись
+
+	// ID записи, которую пользователь переносит
+	RescheduleID string
+
+	// Настройка регулярной записи
+	RecurBookingID string
+	RecurFrequency string
+
+	// Запись, к оценке которой клиент пишет комментарий
+	FeedbackBookingID string
+}
+
+// WaitlistEntry - запись в листе ожидания на занятое время.
+// Пустое Time означает "любое время в этот день"
+type WaitlistEntry struct {
+	ID             int64
+	UserID         int64
+	ChatID         int64
+	Date           string
+	Time           string
+	Status         string
+	OfferedTime    string
+	OfferExpiresAt time.Time
+	Created        time.Time
 }
+
+// Статусы записи в листе ожидания
+const (
+	WaitlistWaiting  = "waiting"  // Ждёт освобождения времени
+	WaitlistOffered  = "offered"  // Предложено освободившееся время
+	WaitlistClaimed  = "claimed"  // Пользователь забрал время и вводит данные
+	WaitlistDone     = "done"     // Записался
+	WaitlistExpired  = "expired"  // Не ответил вовремя
+	WaitlistDeclined = "declined" // Отказался или покинул лист ожидания
+)
+
 type TimeSlot struct {
 	Time      string
 	Available bool
@@ -27,17 +270,23 @@ type TimeSlot struct {
 	CarNumber any
 }
 
-var weekdays = map[time.Weekday]string{
-	time.Sunday:    "Воскресенье",
-	time.Monday:    "Понедельник",
-	time.Tuesday:   "Вторник",
-	time.Wednesday: "Среда",
-	time.Thursday:  "Четверг",
-	time.Friday:    "Пятница",
-	time.Saturday:  "Суббота",
-}
-
 const (
 	DateFormat = "02.01.2006"
 	TimeFormat = "15:04"
 )
+
+// SlotTime возвращает момент начала мойки по дате и времени записи
+func SlotTime(date, timeStr string) (time.Time, error) {
+	return time.ParseInLocation(DateFormat+" "+TimeFormat, date+" "+timeStr, time.Local)
+}
+
+// StartsAt возвращает момент начала мойки
+func (b Booking) StartsAt() (time.Time, error) {
+	return SlotTime(b.Date, b.Time)
+}
+
+// EndsAt возвращает момент окончания мойки без уборки поста
+func (b Booking) EndsAt() (time.Time, error) {
+	start, err := b.StartsAt()
+	return start.Add(time.Duration(b.Duration) * time.Minute), err
+}
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"strings"
+	"time"
+)
+
+// CalendarEvent - событие календаря в формате iCalendar (RFC 5545)
+type CalendarEvent struct {
+	UID         string // Постоянный идентификатор: по нему календарь обновляет событие, а не дублирует его
+	Start       time.Time
+	End         time.Time
+	Summary     string
+	Description string
+	Location    string
+	Tentative   bool // Событие ещё не подтверждено
+}
+
+// BuildCalendar собирает файл .ics из событий. name - название календаря в приложении
+func BuildCalendar(name string, events []CalendarEvent, now time.Time) []byte {
+	var sb strings.Builder
+	writeLine := func(line string) {
+		sb.WriteString(foldLine(line))
+		sb.WriteString("\r\n")
+	}
+
+	writeLine("BEGIN:VCALENDAR")
+	writeLine("VERSION:2.0")
+	writeLine("PRODID:-//carwash-bot//RU")
+	writeLine("CALSCALE:GREGORIAN")
+	writeLine("METHOD:PUBLISH")
+	if name != "" {
+		writeLine("X-WR-CALNAME:" + escapeText(name))
+	}
+
+	stamp := formatUTC(now)
+	for _, event := range events {
+		writeLine("BEGIN:VEVENT")
+		writeLine("UID:" + escapeText(event.UID))
+		writeLine("DTSTAMP:" + stamp)
+		writeLine("DTSTART:" + formatUTC(event.Start))
+		writeLine("DTEND:" + formatUTC(event.End))
+		writeLine("SUMMARY:" + escapeText(event.Summary))
+		if event.Description != "" {
+			writeLine("DESCRIPTION:" + escapeText(event.Description))
+		}
+		if event.Location != "" {
+			writeLine("LOCATION:" + escapeText(event.Location))
+		}
+		if event.Tentative {
+			writeLine("STATUS:TENTATIVE")
+		} else {
+			writeLine("STATUS:CONFIRMED")
+		}
+		writeLine("END:VEVENT")
+	}
+
+	writeLine("END:VCALENDAR")
+	return []byte(sb.String())
+}
+
+func formatUTC(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+// escapeText экранирует спецсимволы текстовых полей
+func escapeText(text string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(text)
+}
+
+// foldLine переносит строки длиннее 75 байт, не разрывая символы UTF-8
+func foldLine(line string) string {
+	const limit = 75
+	if len(line) <= limit {
+		return line
+	}
+
+	var sb strings.Builder
+	width := 0
+	for _, r := range line {
+		size := len(string(r))
+		if width+size > limit {
+			sb.WriteString("\r\n ")
+			width = 1
+		}
+		sb.WriteRune(r)
+		width += size
+	}
+	return sb.String()
+}
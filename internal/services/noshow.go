@@ -0,0 +1,57 @@
+package services
+
+import (
+	"time"
+)
+
+// Меры к клиентам, которые не приезжают на запись
+const (
+	NoShowPenaltyConfirm  = "confirm"  // Записи требуют подтверждения администратора
+	NoShowPenaltyRestrict = "restrict" // Запись только на ближайшие дни
+	NoShowPenaltyBlock    = "block"    // Запись через бота закрыта
+)
+
+// NoShowPolicy - мера, которая применяется после Limit неявок. Limit = 0 отключает меры
+type NoShowPolicy struct {
+	Limit       int
+	Penalty     string
+	HorizonDays int // Горизонт записи при NoShowPenaltyRestrict
+}
+
+// Applies сообщает, действует ли мера для клиента с noShows неявками
+func (p NoShowPolicy) Applies(noShows int) bool {
+	return p.Limit > 0 && noShows >= p.Limit
+}
+
+// RequiresConfirmation сообщает, что записи клиента нужно подтверждать вручную
+func (p NoShowPolicy) RequiresConfirmation(noShows int) bool {
+	return p.Applies(noShows) && p.Penalty == NoShowPenaltyConfirm
+}
+
+// Причины отказа из-за неявок
+const (
+	ReasonNoShowBlocked = "noshow.blocked" // Args: неявок (int)
+	ReasonNoShowHorizon = "noshow.horizon" // Args: дней вперёд (int)
+)
+
+// Check проверяет, может ли клиент записаться на день date. Нулевой date проверяет только блокировку
+func (p NoShowPolicy) Check(now, date time.Time, noShows int) error {
+	if !p.Applies(noShows) {
+		return nil
+	}
+
+	switch p.Penalty {
+	case NoShowPenaltyBlock:
+		return &PolicyError{Reason: ReasonNoShowBlocked, Args: []any{noShows}}
+	case NoShowPenaltyRestrict:
+		if date.IsZero() {
+			return nil
+		}
+		today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+		day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+		if day.After(today.AddDate(0, 0, p.HorizonDays-1)) {
+			return &PolicyError{Reason: ReasonNoShowHorizon, Args: []any{p.HorizonDays}}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package services
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// PlateKind - формат госномера
+type PlateKind string
+
+const (
+	PlateCivil   PlateKind = "civil"   // А123ВС77 - легковые и грузовые
+	PlateTaxi    PlateKind = "taxi"    // АВ12377 - такси и маршрутки
+	PlateTrailer PlateKind = "trailer" // АВ123477 - прицепы
+	PlateForeign PlateKind = "foreign" // Номера других стран: латиница и цифры
+)
+
+// Plate - распознанный госномер в каноническом виде: заглавные буквы без пробелов,
+// российские номера кириллицей, иностранные латиницей
+type Plate struct {
+	Number string
+	Kind   PlateKind
+}
+
+var (
+	ErrNoPlate = errors.New("госномер не распознан")
+	ErrNoModel = errors.New("не указана марка автомобиля")
+)
+
+// В российских номерах используются только буквы, похожие на латинские
+const plateLetters = "АВЕКМНОРСТУХ"
+
+var (
+	civilPlateRe   = regexp.MustCompile(`^[` + plateLetters + `]\d{3}[` + plateLetters + `]{2}\d{2,3}$`)
+	taxiPlateRe    = regexp.MustCompile(`^[` + plateLetters + `]{2}\d{3}\d{2,3}$`)
+	trailerPlateRe = regexp.MustCompile(`^[` + plateLetters + `]{2}\d{4}\d{2,3}$`)
+	foreignPlateRe = regexp.MustCompile(`^[A-Z0-9]{4,10}$`)
+)
+
+// Латинские буквы, которые пишут вместо кириллических, и наоборот
+var (
+	latinToCyrillic = strings.NewReplacer(
+		"A", "А", "B", "В", "E", "Е", "K", "К", "M", "М", "H", "Н",
+		"O", "О", "P", "Р", "C", "С", "T", "Т", "Y", "У", "X", "Х",
+	)
+	cyrillicToLatin = strings.NewReplacer(
+		"А", "A", "В", "B", "Е", "E", "К", "K", "М", "M", "Н", "H",
+		"О", "O", "Р", "P", "С", "C", "Т", "T", "У", "Y", "Х", "X",
+	)
+)
+
+// ParsePlate распознаёт госномер, записанный с пробелами, дефисами,
+// строчными буквами или латиницей вместо кириллицы
+func ParsePlate(text string) (Plate, error) {
+	if plate, ok := parseRussianPlate(text); ok {
+		return plate, nil
+	}
+
+	compact := cyrillicToLatin.Replace(compactPlate(text))
+	if foreignPlateRe.MatchString(compact) && hasLetterAndDigit(compact) {
+		return Plate{Number: compact, Kind: PlateForeign}, nil
+	}
+	return Plate{}, ErrNoPlate
+}
+
+func parseRussianPlate(text string) (Plate, bool) {
+	number := latinToCyrillic.Replace(compactPlate(text))
+
+	switch {
+	case civilPlateRe.MatchString(number):
+		return Plate{Number: number, Kind: PlateCivil}, true
+	case taxiPlateRe.MatchString(number) && trailerPlateRe.MatchString(number):
+		// АВ123477 - это и такси с регионом 477, и прицеп с регионом 77.
+		// Различаем по тому, где пользователь отделил регион
+		if strings.HasSuffix(strings.TrimSpace(text), " "+number[len(number)-2:]) {
+			return Plate{Number: number, Kind: PlateTrailer}, true
+		}
+		return Plate{Number: number, Kind: PlateTaxi}, true
+	case taxiPlateRe.MatchString(number):
+		return Plate{Number: number, Kind: PlateTaxi}, true
+	case trailerPlateRe.MatchString(number):
+		return Plate{Number: number, Kind: PlateTrailer}, true
+	}
+	return Plate{}, false
+}
+
+// ParseCarInfo разбирает строку вида "Lada Vesta А123ВС77" на марку и госномер.
+// Номер может быть в конце или в начале строки и может содержать пробелы
+func ParseCarInfo(text string) (model string, plate Plate, err error) {
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return "", Plate{}, ErrNoPlate
+	}
+
+	// Номер ищется по самому короткому совпадению, чтобы не захватить часть марки ("BMW X5")
+	for size := 1; size <= 4 && size <= len(fields); size++ {
+		if p, ok := parseRussianPlate(strings.Join(fields[len(fields)-size:], " ")); ok {
+			return carModel(fields[:len(fields)-size], p)
+		}
+		if p, ok := parseRussianPlate(strings.Join(fields[:size], " ")); ok {
+			return carModel(fields[size:], p)
+		}
+	}
+
+	// Иностранный номер записывается одним словом
+	if p, err := ParsePlate(fields[len(fields)-1]); err == nil {
+		return carModel(fields[:len(fields)-1], p)
+	}
+	return "", Plate{}, ErrNoPlate
+}
+
+func carModel(fields []string, plate Plate) (string, Plate, error) {
+	if len(fields) == 0 {
+		return "", plate, ErrNoModel
+	}
+	return strings.Join(fields, " "), plate, nil
+}
+
+// compactPlate приводит номер к верхнему регистру и убирает разделители
+func compactPlate(text string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToUpper(text) {
+		if r == ' ' || r == '-' || r == '\t' {
+			continue
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+func hasLetterAndDigit(s string) bool {
+	var letter, digit bool
+	for _, r := range s {
+		letter = letter || unicode.IsLetter(r)
+		digit = digit || unicode.IsDigit(r)
+	}
+	return letter && digit
+}
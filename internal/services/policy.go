@@ -0,0 +1,94 @@
+package services
+
+import (
+	"fmt"
+	"time"
+)
+
+// BookingPolicy описывает правила записи, отмены и переноса
+type BookingPolicy struct {
+	MinNotice      time.Duration // Минимальное время до начала мойки при записи
+	MaxAdvanceDays int           // На сколько дней вперёд можно записаться
+	ChangeCutoff   time.Duration // За сколько до начала запрещены отмена и перенос
+}
+
+// PolicyError - нарушение правил. Reason - ключ сообщения в каталоге i18n, Args - его параметры:
+// бот переводит причину на язык пользователя, time.Duration и time.Time форматирует сам.
+// Если первый параметр - число, сообщение берётся во множественном числе для него
+type PolicyError struct {
+	Reason string
+	Args   []any
+}
+
+func (e *PolicyError) Error() string {
+	if len(e.Args) == 0 {
+		return e.Reason
+	}
+	return fmt.Sprint(e.Reason, e.Args)
+}
+
+// Причины отказа по правилам записи
+const (
+	ReasonPastTime     = "policy.past_time"
+	ReasonMinNotice    = "policy.min_notice"    // Args: минимальное время до записи (time.Duration)
+	ReasonHorizon      = "policy.horizon"       // Args: дней вперёд (int)
+	ReasonStarted      = "policy.started"       // Мойка уже началась, менять её поздно
+	ReasonChangeCutoff = "policy.change_cutoff" // Args: запрет отмены до начала (time.Duration)
+)
+
+// CheckBooking проверяет, можно ли записаться на мойку, начинающуюся в start
+func (p BookingPolicy) CheckBooking(now, start time.Time) error {
+	if !start.After(now) {
+		return &PolicyError{Reason: ReasonPastTime}
+	}
+
+	if start.Before(now.Add(p.MinNotice)) {
+		return &PolicyError{Reason: ReasonMinNotice, Args: []any{p.MinNotice}}
+	}
+
+	if p.MaxAdvanceDays > 0 {
+		today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+		if !start.Before(today.AddDate(0, 0, p.MaxAdvanceDays)) {
+			return &PolicyError{Reason: ReasonHorizon, Args: []any{p.MaxAdvanceDays}}
+		}
+	}
+
+	return nil
+}
+
+// CheckChange проверяет, можно ли отменить или перенести мойку, начинающуюся в start
+func (p BookingPolicy) CheckChange(now, start time.Time) error {
+	if !start.After(now) {
+		return &PolicyError{Reason: ReasonStarted}
+	}
+
+	if start.Before(now.Add(p.ChangeCutoff)) {
+		return &PolicyError{Reason: ReasonChangeCutoff, Args: []any{p.ChangeCutoff}}
+	}
+
+	return nil
+}
+
+// FormatDuration форматирует длительность для сообщений администратору: "1 ч 30 мин".
+// Клиентам длительность показывается на их языке через i18n.Duration
+func FormatDuration(d time.Duration) string {
+	if d <= 0 {
+		return "0 мин"
+	}
+
+	days := int(d / (24 * time.Hour))
+	hours := int(d % (24 * time.Hour) / time.Hour)
+	minutes := int(d % time.Hour / time.Minute)
+
+	var result string
+	if days > 0 {
+		result += fmt.Sprintf("%d дн ", days)
+	}
+	if hours > 0 {
+		result += fmt.Sprintf("%d ч ", hours)
+	}
+	if minutes > 0 || result == "" {
+		result += fmt.Sprintf("%d мин ", minutes)
+	}
+	return result[:len(result)-1]
+}
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBookingPolicyCheckBooking(t *testing.T) {
+	policy := BookingPolicy{MinNotice: 30 * time.Minute, MaxAdvanceDays: 7}
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		name       string
+		policy     BookingPolicy
+		start      time.Time
+		wantReason string // пусто - запись разрешена
+		wantArgs   []any
+	}{
+		{"прошедшее время", policy, now.Add(-time.Hour), ReasonPastTime, nil},
+		{"ровно сейчас", policy, now, ReasonPastTime, nil},
+		{"меньше минимального времени", policy, now.Add(29 * time.Minute), ReasonMinNotice, []any{30 * time.Minute}},
+		{"ровно минимальное время", policy, now.Add(30 * time.Minute), "", nil},
+		{"последний день горизонта", policy, time.Date(2026, 3, 16, 23, 0, 0, 0, time.Local), "", nil},
+		{"за горизонтом", policy, time.Date(2026, 3, 17, 9, 0, 0, 0, time.Local), ReasonHorizon, []any{7}},
+		{"без горизонта", BookingPolicy{}, now.AddDate(1, 0, 0), "", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.CheckBooking(now, tt.start)
+			if tt.wantReason == "" {
+				if err != nil {
+					t.Fatalf("CheckBooking() = %v, ожидалось nil", err)
+				}
+				return
+			}
+
+			var policyErr *PolicyError
+			if !errors.As(err, &policyErr) {
+				t.Fatalf("CheckBooking() = %v, ожидалась PolicyError", err)
+			}
+			if policyErr.Reason != tt.wantReason {
+				t.Errorf("Reason = %q, ожидалась %q", policyErr.Reason, tt.wantReason)
+			}
+			if len(policyErr.Args) != len(tt.wantArgs) {
+				t.Fatalf("Args = %v, ожидались %v", policyErr.Args, tt.wantArgs)
+			}
+			for i := range tt.wantArgs {
+				if policyErr.Args[i] != tt.wantArgs[i] {
+					t.Errorf("Args[%d] = %v, ожидалось %v", i, policyErr.Args[i], tt.wantArgs[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,211 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"time"
+)
+
+// Service - услуга мойки с базовой ценой
+type Service struct {
+	Code     string `json:"code"`
+	Name     string `json:"name"`
+	Price    int    `json:"price"`
+	Duration int    `json:"duration"` // Длительность мойки в минутах
+	Buffer   int    `json:"buffer"`   // Уборка поста после услуги в минутах, 0 - как у поста
+}
+
+// CarClass - класс автомобиля, множитель применяется к базовой цене услуги
+type CarClass struct {
+	Code       string  `json:"code"`
+	Name       string  `json:"name"`
+	Multiplier float64 `json:"multiplier"`
+}
+
+// PriceRule - наценка или скидка. Пустые условия совпадают с любым значением.
+// Все подходящие правила применяются по порядку
+type PriceRule struct {
+	Name       string   `json:"name"`
+	Weekdays   []int    `json:"weekdays"`    // 0 - воскресенье, 6 - суббота
+	From       string   `json:"from"`        // Начало окна, "18:00" (включительно)
+	To         string   `json:"to"`          // Конец окна, "21:00" (не включительно)
+	Services   []string `json:"services"`    // Коды услуг
+	CarClasses []string `json:"car_classes"` // Коды классов авто
+	Percent    int      `json:"percent"`     // Изменение в процентах: 20 - наценка, -10 - скидка
+	Amount     int      `json:"amount"`      // Фиксированное изменение в рублях
+}
+
+// PriceList - прайс-лист с правилами динамического ценообразования
+type PriceList struct {
+	Currency   string      `json:"currency"`
+	Services   []Service   `json:"services"`
+	CarClasses []CarClass  `json:"car_classes"`
+	Rules      []PriceRule `json:"rules"`
+}
+
+// DefaultServiceDuration - длительность мойки в минутах, если она не указана
+const DefaultServiceDuration = 60
+
+// DefaultPriceList используется, если файл с ценами не найден
+func DefaultPriceList() *PriceList {
+	return &PriceList{
+		Currency:   "₽",
+		Services:   []Service{{Code: "wash", Name: "Мойка", Price: 1000, Duration: DefaultServiceDuration}},
+		CarClasses: []CarClass{{Code: "car", Name: "Легковой", Multiplier: 1}},
+	}
+}
+
+// LoadPriceList читает прайс-лист из JSON-файла. Если файла нет, возвращается прайс-лист по умолчанию
+func LoadPriceList(path string) (*PriceList, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return DefaultPriceList(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var list PriceList
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("ошибка разбора %s: %w", path, err)
+	}
+	if len(list.Services) == 0 {
+		return nil, fmt.Errorf("в %s не указано ни одной услуги", path)
+	}
+	for i := range list.Services {
+		if list.Services[i].Duration <= 0 {
+			list.Services[i].Duration = DefaultServiceDuration
+		}
+	}
+	if len(list.CarClasses) == 0 {
+		list.CarClasses = DefaultPriceList().CarClasses
+	}
+	if list.Currency == "" {
+		list.Currency = "₽"
+	}
+	return &list, nil
+}
+
+func (p *PriceList) Service(code string) (Service, bool) {
+	for _, s := range p.Services {
+		if s.Code == code {
+			return s, true
+		}
+	}
+	return Service{}, false
+}
+
+func (p *PriceList) CarClass(code string) (CarClass, bool) {
+	for _, c := range p.CarClasses {
+		if c.Code == code {
+			return c, true
+		}
+	}
+	return CarClass{}, false
+}
+
+// DefaultService - первая услуга прайс-листа
+func (p *PriceList) DefaultService() Service {
+	return p.Services[0]
+}
+
+// DefaultCarClass - первый класс авто прайс-листа
+func (p *PriceList) DefaultCarClass() CarClass {
+	return p.CarClasses[0]
+}
+
+// Price рассчитывает цену услуги для класса авто на время start
+func (p *PriceList) Price(serviceCode, classCode string, start time.Time) (int, error) {
+	service, ok := p.Service(serviceCode)
+	if !ok {
+		return 0, fmt.Errorf("неизвестная услуга %q", serviceCode)
+	}
+	class, ok := p.CarClass(classCode)
+	if !ok {
+		return 0, fmt.Errorf("неизвестный класс авто %q", classCode)
+	}
+
+	price := float64(service.Price) * class.Multiplier
+	for _, rule := range p.Rules {
+		if rule.matches(serviceCode, classCode, start) {
+			price += price * float64(rule.Percent) / 100
+			price += float64(rule.Amount)
+		}
+	}
+
+	if price < 0 {
+		price = 0
+	}
+	return int(math.Round(price)), nil
+}
+
+// IsPeak сообщает, что на время start действует наценка: хотя бы одно подходящее правило повышает цену
+func (p *PriceList) IsPeak(serviceCode, classCode string, start time.Time) bool {
+	for _, rule := range p.Rules {
+		if rule.matches(serviceCode, classCode, start) && (rule.Percent > 0 || rule.Amount > 0) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r PriceRule) matches(serviceCode, classCode string, start time.Time) bool {
+	if len(r.Weekdays) > 0 && !containsInt(r.Weekdays, int(start.Weekday())) {
+		return false
+	}
+	if len(r.Services) > 0 && !containsString(r.Services, serviceCode) {
+		return false
+	}
+	if len(r.CarClasses) > 0 && !containsString(r.CarClasses, classCode) {
+		return false
+	}
+
+	// Время сравнивается строками "15:04", формат фиксированной ширины
+	clock := start.Format("15:04")
+	if r.From != "" && clock < r.From {
+		return false
+	}
+	if r.To != "" && clock >= r.To {
+		return false
+	}
+	return true
+}
+
+// FormatPrice форматирует цену для пользователя: "1 200 ₽"
+func (p *PriceList) FormatPrice(amount int) string {
+	var sb strings.Builder
+	if amount < 0 {
+		sb.WriteRune('-')
+		amount = -amount
+	}
+	digits := fmt.Sprintf("%d", amount)
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			sb.WriteRune(' ')
+		}
+		sb.WriteRune(d)
+	}
+	return sb.String() + " " + p.Currency
+}
+
+func containsInt(values []int, value int) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,111 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+func testPriceList() *PriceList {
+	return &PriceList{
+		Currency: "₽",
+		Services: []Service{
+			{Code: "wash", Name: "Мойка", Price: 1000},
+			{Code: "full", Name: "Комплекс", Price: 2500},
+		},
+		CarClasses: []CarClass{
+			{Code: "car", Name: "Легковой", Multiplier: 1},
+			{Code: "suv", Name: "Внедорожник", Multiplier: 1.5},
+		},
+		Rules: []PriceRule{
+			{Name: "Вечер будней", Weekdays: []int{1, 2, 3, 4, 5}, From: "18:00", To: "21:00", Percent: 20},
+			{Name: "Утро выходных", Weekdays: []int{0, 6}, To: "10:00", Services: []string{"wash"}, Amount: -200},
+			{Name: "Скидка внедорожникам", CarClasses: []string{"suv"}, Percent: -10},
+		},
+	}
+}
+
+func TestPriceListPrice(t *testing.T) {
+	prices := testPriceList()
+	tuesday := func(clock string) time.Time {
+		start, _ := time.ParseInLocation("02.01.2006 15:04", "10.03.2026 "+clock, time.Local)
+		return start
+	}
+	saturday := func(clock string) time.Time {
+		start, _ := time.ParseInLocation("02.01.2006 15:04", "14.03.2026 "+clock, time.Local)
+		return start
+	}
+
+	tests := []struct {
+		name    string
+		service string
+		class   string
+		start   time.Time
+		want    int
+		wantErr bool
+	}{
+		{"базовая цена", "wash", "car", tuesday("12:00"), 1000, false},
+		{"наценка вечером", "wash", "car", tuesday("18:00"), 1200, false},
+		{"конец окна не входит", "wash", "car", tuesday("21:00"), 1000, false},
+		{"множитель класса и скидка", "full", "suv", tuesday("12:00"), 3375, false},
+		{"правила по порядку", "wash", "suv", tuesday("19:00"), 1620, false},
+		{"фиксированная скидка", "wash", "car", saturday("09:00"), 800, false},
+		{"скидка не для этой услуги", "full", "car", saturday("09:00"), 2500, false},
+		{"наценка только в будни", "wash", "car", saturday("19:00"), 1000, false},
+		{"неизвестная услуга", "polish", "car", tuesday("12:00"), 0, true},
+		{"неизвестный класс", "wash", "truck", tuesday("12:00"), 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := prices.Price(tt.service, tt.class, tt.start)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Price() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Price() = %d, ожидалось %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPriceListPriceNotNegative(t *testing.T) {
+	prices := &PriceList{
+		Services:   []Service{{Code: "wash", Price: 100}},
+		CarClasses: []CarClass{{Code: "car", Multiplier: 1}},
+		Rules:      []PriceRule{{Amount: -500}},
+	}
+	if got, err := prices.Price("wash", "car", time.Now()); err != nil || got != 0 {
+		t.Errorf("Price() = %d, %v, ожидалось 0", got, err)
+	}
+}
+
+func TestPriceListIsPeak(t *testing.T) {
+	prices := testPriceList()
+	at := func(date, clock string) time.Time {
+		start, _ := time.ParseInLocation("02.01.2006 15:04", date+" "+clock, time.Local)
+		return start
+	}
+
+	tests := []struct {
+		name    string
+		service string
+		class   string
+		start   time.Time
+		want    bool
+	}{
+		{"наценка вечером в будни", "wash", "car", at("10.03.2026", "18:00"), true},
+		{"наценка вместе со скидкой класса", "full", "suv", at("10.03.2026", "20:00"), true},
+		{"днём наценки нет", "wash", "car", at("10.03.2026", "12:00"), false},
+		{"конец окна не входит", "wash", "car", at("10.03.2026", "21:00"), false},
+		{"скидка не считается наценкой", "wash", "car", at("14.03.2026", "09:00"), false},
+		{"только скидка класса", "full", "suv", at("14.03.2026", "19:00"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := prices.IsPeak(tt.service, tt.class, tt.start); got != tt.want {
+				t.Errorf("IsPeak() = %v, ожидалось %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package services
+
+import (
+	"carwash-bot/internal/models"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Причины, по которым промокод нельзя применить. Reason - ключ сообщения для клиента,
+// отключённый промокод для клиента выглядит как несуществующий
+var (
+	ErrPromoNotFound  = &PolicyError{Reason: "promo.not_found"}
+	ErrPromoInactive  = &PolicyError{Reason: "promo.not_found"}
+	ErrPromoNotYet    = &PolicyError{Reason: "promo.not_yet"}
+	ErrPromoExpired   = &PolicyError{Reason: "promo.expired"}
+	ErrPromoUsedUp    = &PolicyError{Reason: "promo.used_up"}
+	ErrPromoUserLimit = &PolicyError{Reason: "promo.user_limit"}
+	ErrPromoService   = &PolicyError{Reason: "promo.wrong_service"}
+	ErrPromoTime      = &PolicyError{Reason: "promo.wrong_time"}
+)
+
+// Промокод передаётся в ссылке /start, поэтому допустимы только символы, которые разрешает Telegram
+var promoCodeRe = regexp.MustCompile(`^[A-Z0-9_-]{3,32}$`)
+
+// NormalizePromoCode приводит введённый промокод к виду, в котором он хранится
+func NormalizePromoCode(text string) string {
+	return strings.ToUpper(strings.TrimSpace(text))
+}
+
+// ValidPromoCode проверяет формат промокода
+func ValidPromoCode(code string) bool {
+	return promoCodeRe.MatchString(code)
+}
+
+// PromoUsage - сколько раз промокод уже использован
+type PromoUsage struct {
+	Total  int
+	ByUser int
+}
+
+// CheckPromo проверяет, действует ли промокод для мойки с началом start и услугой service.
+// Нулевой start проверяет только то, что промокод ещё можно будет использовать
+func CheckPromo(promo models.PromoCode, now, start time.Time, service string, usage PromoUsage) error {
+	if !promo.Active {
+		return ErrPromoInactive
+	}
+	if promo.MaxUses > 0 && usage.Total >= promo.MaxUses {
+		return ErrPromoUsedUp
+	}
+	if promo.MaxPerUser > 0 && usage.ByUser >= promo.MaxPerUser {
+		return ErrPromoUserLimit
+	}
+
+	// Даты сравниваются без времени: промокод действует весь последний день
+	day := start
+	if day.IsZero() {
+		day = now
+	}
+	if promo.ValidUntil != "" {
+		until, err := time.ParseInLocation(models.DateFormat, promo.ValidUntil, time.Local)
+		if err == nil && !day.Before(until.AddDate(0, 0, 1)) {
+			return ErrPromoExpired
+		}
+	}
+	if start.IsZero() {
+		return nil
+	}
+
+	if promo.ValidFrom != "" {
+		from, err := time.ParseInLocation(models.DateFormat, promo.ValidFrom, time.Local)
+		if err == nil && start.Before(from) {
+			return ErrPromoNotYet
+		}
+	}
+	if len(promo.Services) > 0 && !containsString(promo.Services, service) {
+		return ErrPromoService
+	}
+	clock := start.Format("15:04")
+	if (promo.TimeFrom != "" && clock < promo.TimeFrom) || (promo.TimeTo != "" && clock >= promo.TimeTo) {
+		return ErrPromoTime
+	}
+	return nil
+}
+
+// PromoDiscount - скидка по промокоду для цены price. Скидка не больше самой цены
+func PromoDiscount(promo models.PromoCode, price int) int {
+	discount := promo.Amount
+	if promo.Percent > 0 {
+		discount = price * promo.Percent / 100
+	}
+	if discount > price {
+		discount = price
+	}
+	if discount < 0 {
+		discount = 0
+	}
+	return discount
+}
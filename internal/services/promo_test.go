@@ -0,0 +1,88 @@
+package services
+
+import (
+	"carwash-bot/internal/models"
+	"testing"
+	"time"
+)
+
+func TestCheckPromo(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.Local)
+	at := func(date, clock string) time.Time {
+		start, _ := time.ParseInLocation("02.01.2006 15:04", date+" "+clock, time.Local)
+		return start
+	}
+	promo := models.PromoCode{
+		Code:       "SPRING",
+		Percent:    20,
+		ValidFrom:  "15.03.2026",
+		ValidUntil: "31.03.2026",
+		MaxUses:    100,
+		MaxPerUser: 1,
+		Services:   []string{"wash"},
+		TimeFrom:   "10:00",
+		TimeTo:     "16:00",
+		Active:     true,
+	}
+	with := func(change func(p *models.PromoCode)) models.PromoCode {
+		p := promo
+		change(&p)
+		return p
+	}
+
+	tests := []struct {
+		name    string
+		promo   models.PromoCode
+		start   time.Time
+		service string
+		usage   PromoUsage
+		want    error
+	}{
+		{"подходит", promo, at("20.03.2026", "12:00"), "wash", PromoUsage{}, nil},
+		{"отключён", with(func(p *models.PromoCode) { p.Active = false }), at("20.03.2026", "12:00"), "wash", PromoUsage{}, ErrPromoInactive},
+		{"исчерпан", promo, at("20.03.2026", "12:00"), "wash", PromoUsage{Total: 100}, ErrPromoUsedUp},
+		{"клиент уже использовал", promo, at("20.03.2026", "12:00"), "wash", PromoUsage{ByUser: 1}, ErrPromoUserLimit},
+		{"ещё не действует", promo, at("14.03.2026", "12:00"), "wash", PromoUsage{}, ErrPromoNotYet},
+		{"действует весь последний день", promo, at("31.03.2026", "15:00"), "wash", PromoUsage{}, nil},
+		{"срок истёк", promo, at("01.04.2026", "12:00"), "wash", PromoUsage{}, ErrPromoExpired},
+		{"другая услуга", promo, at("20.03.2026", "12:00"), "full", PromoUsage{}, ErrPromoService},
+		{"раньше окна", promo, at("20.03.2026", "09:00"), "wash", PromoUsage{}, ErrPromoTime},
+		{"конец окна не входит", promo, at("20.03.2026", "16:00"), "wash", PromoUsage{}, ErrPromoTime},
+		{"без записи до начала действия", promo, time.Time{}, "", PromoUsage{}, nil},
+		{"без записи после срока", with(func(p *models.PromoCode) { p.ValidUntil = "09.03.2026" }), time.Time{}, "", PromoUsage{}, ErrPromoExpired},
+		{"без ограничений", models.PromoCode{Code: "ANY", Amount: 100, Active: true}, at("01.01.2027", "23:00"), "full", PromoUsage{Total: 1000, ByUser: 10}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CheckPromo(tt.promo, now, tt.start, tt.service, tt.usage); got != tt.want {
+				t.Errorf("CheckPromo() = %v, ожидалось %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPromoDiscount(t *testing.T) {
+	tests := []struct {
+		name  string
+		promo models.PromoCode
+		price int
+		want  int
+	}{
+		{"процент", models.PromoCode{Percent: 20}, 1500, 300},
+		{"процент округляется вниз", models.PromoCode{Percent: 15}, 999, 149},
+		{"фиксированная сумма", models.PromoCode{Amount: 300}, 1500, 300},
+		{"процент важнее суммы", models.PromoCode{Percent: 10, Amount: 500}, 1000, 100},
+		{"не больше цены", models.PromoCode{Amount: 2000}, 1500, 1500},
+		{"бесплатная мойка", models.PromoCode{Percent: 50}, 0, 0},
+		{"отрицательная сумма", models.PromoCode{Amount: -100}, 1000, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PromoDiscount(tt.promo, tt.price); got != tt.want {
+				t.Errorf("PromoDiscount() = %d, ожидалось %d", got, tt.want)
+			}
+		})
+	}
+}
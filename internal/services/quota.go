@@ -0,0 +1,78 @@
+package services
+
+import "time"
+
+// QuotaPolicy ограничивает количество записей одного пользователя.
+// Нулевое значение лимита означает отсутствие ограничения
+type QuotaPolicy struct {
+	MaxActive      int           // Активных (будущих) записей на пользователя
+	MaxPerDay      int           // Записей пользователя на один день
+	MaxPerPlate    int           // Активных записей на один номер машины
+	CancelLimit    int           // Сколько отмен за CancelWindow включают паузу
+	CancelWindow   time.Duration // Окно подсчёта отмен
+	CancelCooldown time.Duration // Пауза после последней отмены
+}
+
+// QuotaUsage - текущее использование лимитов пользователем
+type QuotaUsage struct {
+	Active        int
+	OnDay         int
+	OnPlate       int
+	Cancellations []time.Time // Моменты отмен пользователем
+}
+
+// Причины отказа по лимитам записей
+const (
+	ReasonCooldown    = "quota.cooldown"      // Args: когда закончится пауза (time.Time)
+	ReasonMaxActive   = "quota.max_active"    // Args: активных записей (int)
+	ReasonMaxPerDay   = "quota.max_per_day"   // Args: лимит на день (int)
+	ReasonMaxPerPlate = "quota.max_per_plate" // Args: записей на номер (int)
+)
+
+// Check проверяет, может ли пользователь создать ещё одну запись
+func (q QuotaPolicy) Check(now time.Time, usage QuotaUsage) error {
+	if until := q.CooldownUntil(now, usage.Cancellations); !until.IsZero() {
+		return &PolicyError{Reason: ReasonCooldown, Args: []any{until}}
+	}
+
+	if q.MaxActive > 0 && usage.Active >= q.MaxActive {
+		return &PolicyError{Reason: ReasonMaxActive, Args: []any{usage.Active}}
+	}
+
+	if q.MaxPerDay > 0 && usage.OnDay >= q.MaxPerDay {
+		return &PolicyError{Reason: ReasonMaxPerDay, Args: []any{q.MaxPerDay}}
+	}
+
+	if q.MaxPerPlate > 0 && usage.OnPlate >= q.MaxPerPlate {
+		return &PolicyError{Reason: ReasonMaxPerPlate, Args: []any{usage.OnPlate}}
+	}
+
+	return nil
+}
+
+// CooldownUntil возвращает момент окончания паузы после частых отмен или нулевое время
+func (q QuotaPolicy) CooldownUntil(now time.Time, cancellations []time.Time) time.Time {
+	if q.CancelLimit <= 0 {
+		return time.Time{}
+	}
+
+	var recent int
+	var last time.Time
+	for _, at := range cancellations {
+		if q.CancelWindow > 0 && at.Before(now.Add(-q.CancelWindow)) {
+			continue
+		}
+		recent++
+		if at.After(last) {
+			last = at
+		}
+	}
+
+	if recent < q.CancelLimit {
+		return time.Time{}
+	}
+	if until := last.Add(q.CancelCooldown); now.Before(until) {
+		return until
+	}
+	return time.Time{}
+}
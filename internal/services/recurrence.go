@@ -0,0 +1,56 @@
+package services
+
+import "time"
+
+// Периодичность регулярной записи
+const (
+	FrequencyWeekly   = "weekly"
+	FrequencyBiweekly = "biweekly"
+	FrequencyMonthly  = "monthly"
+)
+
+// FrequencyNames - названия периодичности для канала администраторов.
+// Клиенту периодичность показывается по ключу recurring.freq.<код> на его языке
+var FrequencyNames = map[string]string{
+	FrequencyWeekly:   "каждую неделю",
+	FrequencyBiweekly: "раз в две недели",
+	FrequencyMonthly:  "каждый месяц",
+}
+
+// RecurrenceDates возвращает даты повторений после start (сама start не входит).
+// Серия ограничивается количеством повторений count (включая start) или датой until,
+// а также maxCount как верхним пределом. Для ежемесячной серии месяцы,
+// в которых нет такого числа (например, 31-го), пропускаются.
+func RecurrenceDates(start time.Time, frequency string, count int, until time.Time, maxCount int) []time.Time {
+	if count <= 0 || count > maxCount {
+		count = maxCount
+	}
+
+	var dates []time.Time
+	for i := 1; len(dates) < count-1; i++ {
+		var next time.Time
+		switch frequency {
+		case FrequencyWeekly:
+			next = start.AddDate(0, 0, 7*i)
+		case FrequencyBiweekly:
+			next = start.AddDate(0, 0, 14*i)
+		case FrequencyMonthly:
+			next = start.AddDate(0, i, 0)
+			if next.Day() != start.Day() {
+				// Защита от бесконечного цикла, если число не встречается слишком долго
+				if i > maxCount*2 {
+					return dates
+				}
+				continue
+			}
+		default:
+			return nil
+		}
+
+		if !until.IsZero() && next.After(until) {
+			break
+		}
+		dates = append(dates, next)
+	}
+	return dates
+}
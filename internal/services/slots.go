@@ -0,0 +1,141 @@
+package services
+
+import (
+	"sort"
+	"time"
+)
+
+// Bay - пост мойки со своим временем на уборку после машины
+type Bay struct {
+	Number int
+	Buffer time.Duration
+}
+
+// Occupied - интервал, на который занят пост, включая уборку после мойки
+type Occupied struct {
+	Bay   int
+	Start time.Time
+	End   time.Time
+}
+
+// FreeBays возвращает посты, на которых можно провести мойку с start длительностью duration.
+// После мойки пост убирается: если у услуги задан serviceBuffer, используется он,
+// иначе - время уборки поста
+func FreeBays(occupied []Occupied, bays []Bay, start time.Time, duration, serviceBuffer time.Duration) []int {
+	var free []int
+	for _, bay := range bays {
+		buffer := bay.Buffer
+		if serviceBuffer > 0 {
+			buffer = serviceBuffer
+		}
+		end := start.Add(duration + buffer)
+
+		busy := false
+		for _, o := range occupied {
+			if o.Bay == bay.Number && start.Before(o.End) && o.Start.Before(end) {
+				busy = true
+				break
+			}
+		}
+		if !busy {
+			free = append(free, bay.Number)
+		}
+	}
+	return free
+}
+
+// BufferFor возвращает время уборки для мойки на посту bay
+func BufferFor(bays []Bay, bay int, serviceBuffer time.Duration) time.Duration {
+	if serviceBuffer > 0 {
+		return serviceBuffer
+	}
+	for _, b := range bays {
+		if b.Number == bay {
+			return b.Buffer
+		}
+	}
+	return 0
+}
+
+// ShiftWindow - рабочее время мойщика
+type ShiftWindow struct {
+	StaffID int64
+	Start   time.Time
+	End     time.Time
+}
+
+// Assignment - интервал, на который мойщик занят машиной
+type Assignment struct {
+	StaffID int64
+	Start   time.Time
+	End     time.Time
+}
+
+// FreeWashers возвращает мойщиков, чья смена целиком покрывает [start, end)
+// и кто в это время не занят другой машиной. Первыми идут наименее загруженные за день
+func FreeWashers(shifts []ShiftWindow, assignments []Assignment, start, end time.Time) []int64 {
+	load := make(map[int64]int)
+	for _, a := range assignments {
+		load[a.StaffID]++
+	}
+
+	var free []int64
+	seen := make(map[int64]bool)
+	for _, shift := range shifts {
+		if seen[shift.StaffID] || start.Before(shift.Start) || end.After(shift.End) {
+			continue
+		}
+
+		busy := false
+		for _, a := range assignments {
+			if a.StaffID == shift.StaffID && start.Before(a.End) && a.Start.Before(end) {
+				busy = true
+				break
+			}
+		}
+		if !busy {
+			seen[shift.StaffID] = true
+			free = append(free, shift.StaffID)
+		}
+	}
+
+	sort.SliceStable(free, func(i, j int) bool {
+		return load[free[i]] < load[free[j]]
+	})
+	return free
+}
+
+// EstimateStarts оценивает, когда освободится пост для каждого из count клиентов живой очереди.
+// Записи в occupied не сдвигаются: клиенты очереди занимают промежутки между ними
+func EstimateStarts(occupied []Occupied, bays []Bay, now time.Time, count int, duration, serviceBuffer time.Duration) []time.Time {
+	planned := append([]Occupied(nil), occupied...)
+
+	var starts []time.Time
+	for len(starts) < count {
+		// Пост может освободиться только сейчас или в момент окончания какой-то мойки
+		candidates := []time.Time{now}
+		for _, o := range planned {
+			if o.End.After(now) {
+				candidates = append(candidates, o.End)
+			}
+		}
+		sort.Slice(candidates, func(i, j int) bool { return candidates[i].Before(candidates[j]) })
+
+		found := false
+		for _, start := range candidates {
+			free := FreeBays(planned, bays, start, duration, serviceBuffer)
+			if len(free) == 0 {
+				continue
+			}
+			end := start.Add(duration + BufferFor(bays, free[0], serviceBuffer))
+			planned = append(planned, Occupied{Bay: free[0], Start: start, End: end})
+			starts = append(starts, start)
+			found = true
+			break
+		}
+		if !found {
+			break
+		}
+	}
+	return starts
+}
@@ -0,0 +1,44 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFreeBays(t *testing.T) {
+	day := time.Date(2026, 3, 10, 0, 0, 0, 0, time.Local)
+	at := func(hour, minute int) time.Time {
+		return day.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+	}
+	bays := []Bay{{Number: 1, Buffer: 15 * time.Minute}, {Number: 2}}
+
+	tests := []struct {
+		name          string
+		occupied      []Occupied
+		start         time.Time
+		serviceBuffer time.Duration
+		want          []int
+	}{
+		{"всё свободно", nil, at(10, 0), 0, []int{1, 2}},
+		{"пост занят в это время", []Occupied{{Bay: 1, Start: at(10, 0), End: at(11, 15)}}, at(10, 0), 0, []int{2}},
+		{"мойка начинается после уборки", []Occupied{{Bay: 1, Start: at(9, 0), End: at(10, 15)}}, at(10, 15), 0, []int{1, 2}},
+		{"мешает уборка предыдущей мойки", []Occupied{{Bay: 1, Start: at(9, 0), End: at(10, 15)}}, at(10, 0), 0, []int{2}},
+		{"уборка поста задевает следующую запись", []Occupied{{Bay: 1, Start: at(11, 0), End: at(12, 0)}}, at(10, 0), 0, []int{2}},
+		{"уборка услуги вместо уборки поста", []Occupied{{Bay: 2, Start: at(11, 0), End: at(12, 0)}}, at(10, 0), 5 * time.Minute, []int{1}},
+		{"следующая запись сразу после мойки", []Occupied{{Bay: 2, Start: at(11, 0), End: at(12, 0)}}, at(10, 0), 0, []int{1, 2}},
+		{"все посты заняты", []Occupied{
+			{Bay: 1, Start: at(10, 0), End: at(11, 15)},
+			{Bay: 2, Start: at(10, 30), End: at(11, 30)},
+		}, at(10, 0), 0, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FreeBays(tt.occupied, bays, tt.start, time.Hour, tt.serviceBuffer)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FreeBays() = %v, ожидалось %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package storage
+
+import (
+	"carwash-bot/internal/models"
+	"database/sql"
+	"time"
+)
+
+// GetCustomer возвращает профиль клиента или nil, если клиент ещё не сохранялся
+func (s *SQLiteStorage) GetCustomer(userID int64) (*models.Customer, error) {
+	var customer models.Customer
+	err := s.db.QueryRow(`
+		SELECT user_id, phone, language, promo_code, updated_at FROM customers WHERE user_id = ?
+	`, userID).Scan(&customer.UserID, &customer.Phone, &customer.Language, &customer.PromoCode, &customer.Updated)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &customer, nil
+}
+
+// SetCustomerPhone сохраняет телефон клиента
+func (s *SQLiteStorage) SetCustomerPhone(userID int64, phone string) error {
+	_, err := s.db.Exec(`
+		INSERT INTO customers (user_id, phone, updated_at) VALUES (?, ?, ?)
+		ON CONFLICT(user_id) DO UPDATE SET phone = excluded.phone, updated_at = excluded.updated_at
+	`, userID, phone, time.Now())
+	return err
+}
+
+// SetCustomerLanguage сохраняет язык, выбранный клиентом в настройках
+func (s *SQLiteStorage) SetCustomerLanguage(userID int64, language string) error {
+	_, err := s.db.Exec(`
+		INSERT INTO customers (user_id, language, updated_at) VALUES (?, ?, ?)
+		ON CONFLICT(user_id) DO UPDATE SET language = excluded.language, updated_at = excluded.updated_at
+	`, userID, language, time.Now())
+	return err
+}
+
+// SetCustomerPromo запоминает промокод из ссылки до следующей записи. Пустой code его сбрасывает
+func (s *SQLiteStorage) SetCustomerPromo(userID int64, code string) error {
+	_, err := s.db.Exec(`
+		INSERT INTO customers (user_id, promo_code, updated_at) VALUES (?, ?, ?)
+		ON CONFLICT(user_id) DO UPDATE SET promo_code = excluded.promo_code, updated_at = excluded.updated_at
+	`, userID, code, time.Now())
+	return err
+}
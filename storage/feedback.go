@@ -0,0 +1,72 @@
+package storage
+
+import (
+	"carwash-bot/internal/models"
+	"database/sql"
+	"time"
+)
+
+const feedbackColumns = `booking_id, user_id, washer_id, rating, comment, created_at, updated_at`
+
+func scanFeedback(row interface{ Scan(...any) error }) (models.Feedback, error) {
+	var f models.Feedback
+	var created int64
+	err := row.Scan(&f.BookingID, &f.UserID, &f.WasherID, &f.Rating, &f.Comment, &created, &f.Updated)
+	f.Created = time.Unix(created, 0)
+	return f, err
+}
+
+// SaveFeedbackRating сохраняет оценку записи. Повторная оценка заменяет прежнюю, комментарий сохраняется
+func (s *SQLiteStorage) SaveFeedbackRating(feedback models.Feedback) error {
+	_, err := s.db.Exec(`
+		INSERT INTO feedback (booking_id, user_id, washer_id, rating, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(booking_id) DO UPDATE SET rating = excluded.rating, updated_at = excluded.updated_at
+	`, feedback.BookingID, feedback.UserID, feedback.WasherID, feedback.Rating, time.Now().Unix(), time.Now())
+	return err
+}
+
+// SetFeedbackComment добавляет комментарий к уже поставленной оценке
+func (s *SQLiteStorage) SetFeedbackComment(bookingID, comment string) error {
+	_, err := s.db.Exec(`
+		UPDATE feedback SET comment = ?, updated_at = ? WHERE booking_id = ?
+	`, comment, time.Now(), bookingID)
+	return err
+}
+
+// GetFeedback возвращает оценку записи или nil, если клиент её ещё не ставил
+func (s *SQLiteStorage) GetFeedback(bookingID string) (*models.Feedback, error) {
+	f, err := scanFeedback(s.db.QueryRow(`
+		SELECT `+feedbackColumns+` FROM feedback WHERE booking_id = ?
+	`, bookingID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+// FeedbackSince возвращает оценки, поставленные начиная с since, новые первыми
+func (s *SQLiteStorage) FeedbackSince(since time.Time) ([]models.Feedback, error) {
+	rows, err := s.db.Query(`
+		SELECT `+feedbackColumns+` FROM feedback
+		WHERE created_at >= ?
+		ORDER BY created_at DESC
+	`, since.Unix())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var feedback []models.Feedback
+	for rows.Next() {
+		f, err := scanFeedback(rows)
+		if err != nil {
+			return nil, err
+		}
+		feedback = append(feedback, f)
+	}
+	return feedback, rows.Err()
+}
@@ -0,0 +1,49 @@
+package storage
+
+import "carwash-bot/internal/models"
+
+// AddBookingEvent добавляет событие в историю записи
+func (s *SQLiteStorage) AddBookingEvent(event models.BookingEvent) error {
+	_, err := s.db.Exec(`
+		INSERT INTO booking_history (booking_id, user_id, action, details, actor_id, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, event.BookingID, event.UserID, event.Action, event.Details, event.ActorID, event.Created)
+	return err
+}
+
+func (s *SQLiteStorage) GetBookingHistory(bookingID string) ([]models.BookingEvent, error) {
+	return s.queryEvents(`
+		SELECT id, booking_id, user_id, action, details, actor_id, created_at
+		FROM booking_history
+		WHERE booking_id = ?
+		ORDER BY id
+	`, bookingID)
+}
+
+// GetUserActions возвращает действия, которые пользователь сам совершил со своими записями
+func (s *SQLiteStorage) GetUserActions(userID int64, action string) ([]models.BookingEvent, error) {
+	return s.queryEvents(`
+		SELECT id, booking_id, user_id, action, details, actor_id, created_at
+		FROM booking_history
+		WHERE user_id = ? AND actor_id = ? AND action = ?
+		ORDER BY id
+	`, userID, userID, action)
+}
+
+func (s *SQLiteStorage) queryEvents(query string, args ...any) ([]models.BookingEvent, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []models.BookingEvent
+	for rows.Next() {
+		var e models.BookingEvent
+		if err := rows.Scan(&e.ID, &e.BookingID, &e.UserID, &e.Action, &e.Details, &e.ActorID, &e.Created); err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
@@ -0,0 +1,87 @@
+package storage
+
+import (
+	"carwash-bot/internal/models"
+	"time"
+)
+
+const jobColumns = `id, kind, booking_id, slot, offset_minutes, run_at, status, created_at`
+
+func scanJob(row interface{ Scan(...any) error }) (models.Job, error) {
+	var j models.Job
+	var runAt int64
+	err := row.Scan(&j.ID, &j.Kind, &j.BookingID, &j.Slot, &j.OffsetMinutes, &runAt, &j.Status, &j.Created)
+	j.RunAt = time.Unix(runAt, 0)
+	return j, err
+}
+
+// ScheduleJob добавляет задачу. Повторное планирование той же задачи
+// (тот же вид, запись, время записи и смещение) ничего не меняет
+func (s *SQLiteStorage) ScheduleJob(job models.Job) error {
+	_, err := s.db.Exec(`
+		INSERT OR IGNORE INTO jobs (kind, booking_id, slot, offset_minutes, run_at, status, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, job.Kind, job.BookingID, job.Slot, job.OffsetMinutes, job.RunAt.Unix(), models.JobPending, time.Now())
+	return err
+}
+
+// DueJobs возвращает задачи, время которых наступило, в порядке времени запуска
+func (s *SQLiteStorage) DueJobs(now time.Time) ([]models.Job, error) {
+	rows, err := s.db.Query(`
+		SELECT `+jobColumns+` FROM jobs
+		WHERE status = ? AND run_at <= ?
+		ORDER BY run_at, id
+	`, models.JobPending, now.Unix())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var jobs []models.Job
+	for rows.Next() {
+		j, err := scanJob(rows)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, j)
+	}
+	return jobs, rows.Err()
+}
+
+// ClaimJob забирает задачу на выполнение. Возвращает false, если её уже забрали,
+// так что каждая задача выполняется не больше одного раза
+func (s *SQLiteStorage) ClaimJob(id int64) (bool, error) {
+	res, err := s.db.Exec(`
+		UPDATE jobs SET status = ? WHERE id = ? AND status = ?
+	`, models.JobRunning, id, models.JobPending)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n == 1, err
+}
+
+// ResetRunningJobs возвращает в очередь задачи, которые остались забранными после
+// падения или перезапуска бота. Вызывается при запуске, пока ни одна задача не выполняется
+func (s *SQLiteStorage) ResetRunningJobs() (int64, error) {
+	res, err := s.db.Exec(`UPDATE jobs SET status = ? WHERE status = ?`, models.JobPending, models.JobRunning)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func (s *SQLiteStorage) SetJobStatus(id int64, status string) error {
+	_, err := s.db.Exec(`UPDATE jobs SET status = ? WHERE id = ?`, status, id)
+	return err
+}
+
+// ExpediteJobs переносит ожидающие задачи записи на более раннее время runAt.
+// Задачи, которые и так должны выполниться раньше, не меняются
+func (s *SQLiteStorage) ExpediteJobs(kind, bookingID string, runAt time.Time) error {
+	_, err := s.db.Exec(`
+		UPDATE jobs SET run_at = ?
+		WHERE kind = ? AND booking_id = ? AND status = ? AND run_at > ?
+	`, runAt.Unix(), kind, bookingID, models.JobPending, runAt.Unix())
+	return err
+}
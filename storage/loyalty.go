@@ -0,0 +1,64 @@
+package storage
+
+import (
+	"carwash-bot/internal/models"
+	"strings"
+)
+
+// AddLoyaltyEntry добавляет операцию в журнал баллов
+func (s *SQLiteStorage) AddLoyaltyEntry(entry models.LoyaltyEntry) error {
+	_, err := s.db.Exec(`
+		INSERT INTO loyalty_ledger (user_id, booking_id, delta, reason, comment, actor_id, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, entry.UserID, entry.BookingID, entry.Delta, entry.Reason, entry.Comment, entry.ActorID, entry.Created)
+	return err
+}
+
+// LoyaltyBalance возвращает баланс баллов клиента
+func (s *SQLiteStorage) LoyaltyBalance(userID int64) (int, error) {
+	var balance int
+	err := s.db.QueryRow(`
+		SELECT COALESCE(SUM(delta), 0) FROM loyalty_ledger WHERE user_id = ?
+	`, userID).Scan(&balance)
+	return balance, err
+}
+
+// LoyaltyBookingDelta возвращает сумму операций по записи с указанными причинами.
+// Нужна, чтобы не начислить и не вернуть баллы за одну запись дважды
+func (s *SQLiteStorage) LoyaltyBookingDelta(bookingID string, reasons ...string) (int, error) {
+	args := []any{bookingID}
+	for _, reason := range reasons {
+		args = append(args, reason)
+	}
+	var delta int
+	err := s.db.QueryRow(`
+		SELECT COALESCE(SUM(delta), 0) FROM loyalty_ledger
+		WHERE booking_id = ? AND reason IN (?`+strings.Repeat(", ?", len(reasons)-1)+`)
+	`, args...).Scan(&delta)
+	return delta, err
+}
+
+// LoyaltyLedger возвращает последние операции клиента, новые первыми
+func (s *SQLiteStorage) LoyaltyLedger(userID int64, limit int) ([]models.LoyaltyEntry, error) {
+	rows, err := s.db.Query(`
+		SELECT id, user_id, booking_id, delta, reason, comment, actor_id, created_at
+		FROM loyalty_ledger
+		WHERE user_id = ?
+		ORDER BY id DESC
+		LIMIT ?
+	`, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []models.LoyaltyEntry
+	for rows.Next() {
+		var e models.LoyaltyEntry
+		if err := rows.Scan(&e.ID, &e.UserID, &e.BookingID, &e.Delta, &e.Reason, &e.Comment, &e.ActorID, &e.Created); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
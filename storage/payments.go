@@ -0,0 +1,34 @@
+package storage
+
+import (
+	"carwash-bot/internal/models"
+	"database/sql"
+)
+
+// AddPayment сохраняет платёж. Повторное уведомление о том же платеже ничего не меняет
+func (s *SQLiteStorage) AddPayment(payment models.Payment) error {
+	_, err := s.db.Exec(`
+		INSERT OR IGNORE INTO payments (booking_id, user_id, amount, currency, telegram_charge_id, provider_charge_id, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, payment.BookingID, payment.UserID, payment.Amount, payment.Currency, payment.TelegramChargeID,
+		payment.ProviderChargeID, payment.Created)
+	return err
+}
+
+// GetBookingPayment возвращает платёж по записи или nil, если запись не оплачивалась
+func (s *SQLiteStorage) GetBookingPayment(bookingID string) (*models.Payment, error) {
+	var p models.Payment
+	err := s.db.QueryRow(`
+		SELECT id, booking_id, user_id, amount, currency, telegram_charge_id, provider_charge_id, created_at
+		FROM payments WHERE booking_id = ?
+		ORDER BY id DESC LIMIT 1
+	`, bookingID).Scan(&p.ID, &p.BookingID, &p.UserID, &p.Amount, &p.Currency, &p.TelegramChargeID,
+		&p.ProviderChargeID, &p.Created)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
@@ -0,0 +1,83 @@
+package storage
+
+import (
+	"carwash-bot/internal/models"
+	"database/sql"
+	"strings"
+)
+
+const promoColumns = `code, percent, amount, valid_from, valid_until, max_uses, max_per_user, services,
+	time_from, time_to, active, created_at`
+
+func scanPromo(row interface{ Scan(...any) error }) (models.PromoCode, error) {
+	var p models.PromoCode
+	var services string
+	err := row.Scan(&p.Code, &p.Percent, &p.Amount, &p.ValidFrom, &p.ValidUntil, &p.MaxUses, &p.MaxPerUser,
+		&services, &p.TimeFrom, &p.TimeTo, &p.Active, &p.Created)
+	if services != "" {
+		p.Services = strings.Split(services, ",")
+	}
+	return p, err
+}
+
+// AddPromoCode добавляет промокод. Код должен быть уникальным
+func (s *SQLiteStorage) AddPromoCode(promo models.PromoCode) error {
+	_, err := s.db.Exec(`
+		INSERT INTO promo_codes (`+promoColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, promo.Code, promo.Percent, promo.Amount, promo.ValidFrom, promo.ValidUntil, promo.MaxUses, promo.MaxPerUser,
+		strings.Join(promo.Services, ","), promo.TimeFrom, promo.TimeTo, promo.Active, promo.Created)
+	return err
+}
+
+// GetPromoCode возвращает промокод или nil, если такого нет
+func (s *SQLiteStorage) GetPromoCode(code string) (*models.PromoCode, error) {
+	promo, err := scanPromo(s.db.QueryRow(`SELECT `+promoColumns+` FROM promo_codes WHERE code = ?`, code))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &promo, nil
+}
+
+func (s *SQLiteStorage) ListPromoCodes() ([]models.PromoCode, error) {
+	rows, err := s.db.Query(`SELECT ` + promoColumns + ` FROM promo_codes ORDER BY created_at DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var promos []models.PromoCode
+	for rows.Next() {
+		p, err := scanPromo(rows)
+		if err != nil {
+			return nil, err
+		}
+		promos = append(promos, p)
+	}
+	return promos, rows.Err()
+}
+
+// SetPromoActive включает или выключает промокод. Возвращает sql.ErrNoRows, если кода нет
+func (s *SQLiteStorage) SetPromoActive(code string, active bool) error {
+	res, err := s.db.Exec(`UPDATE promo_codes SET active = ? WHERE code = ?`, active, code)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// PromoUsage считает записи с промокодом: всего и у клиента userID.
+// Отменённые записи удаляются и использование промокода не расходуют
+func (s *SQLiteStorage) PromoUsage(code string, userID int64) (total, byUser int, err error) {
+	err = s.db.QueryRow(`
+		SELECT COUNT(*), COALESCE(SUM(CASE WHEN user_id = ? THEN 1 ELSE 0 END), 0)
+		FROM bookings WHERE promo_code = ?
+	`, userID, code).Scan(&total, &byUser)
+	return total, byUser, err
+}
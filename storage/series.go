@@ -0,0 +1,50 @@
+package storage
+
+import (
+	"carwash-bot/internal/models"
+	"database/sql"
+)
+
+// AddSeries сохраняет регулярную запись и возвращает её ID
+func (s *SQLiteStorage) AddSeries(series models.BookingSeries) (int64, error) {
+	res, err := s.db.Exec(`
+		INSERT INTO booking_series (user_id, frequency, start_date, time, until_date, count, car_model, car_number, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, series.UserID, series.Frequency, series.StartDate, series.Time, series.UntilDate, series.Count,
+		series.CarModel, series.CarNumber, series.Created)
+	if err != nil {
+		return 0, err
+	}
+	return res.LastInsertId()
+}
+
+func (s *SQLiteStorage) GetSeries(id int64) (*models.BookingSeries, error) {
+	var series models.BookingSeries
+	err := s.db.QueryRow(`
+		SELECT id, user_id, frequency, start_date, time, until_date, count, car_model, car_number, created_at
+		FROM booking_series
+		WHERE id = ?
+	`, id).Scan(&series.ID, &series.UserID, &series.Frequency, &series.StartDate, &series.Time,
+		&series.UntilDate, &series.Count, &series.CarModel, &series.CarNumber, &series.Created)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &series, nil
+}
+
+// SetBookingSeries привязывает существующую запись к серии
+func (s *SQLiteStorage) SetBookingSeries(bookingID string, seriesID int64) error {
+	_, err := s.db.Exec("UPDATE bookings SET series_id = ? WHERE id = ?", seriesID, bookingID)
+	return err
+}
+
+func (s *SQLiteStorage) GetSeriesBookings(seriesID int64) ([]models.Booking, error) {
+	return s.queryBookings(`
+		SELECT `+bookingColumns+`
+		FROM bookings
+		WHERE series_id = ?
+	`, seriesID)
+}
@@ -2,10 +2,18 @@ package storage
 
 import (
 	"carwash-bot/internal/models"
+	"carwash-bot/internal/services"
 	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
 	_ "modernc.org/sqlite"
 )
 
+// ErrSlotTaken - выбранное время уже занято другой записью
+var ErrSlotTaken = errors.New("slot already taken")
+
 type SQLiteStorage struct {
 	db        *sql.DB
 	StartTime int
@@ -32,10 +40,148 @@ func NewSQLiteStorage(dbPath string, startTime, endTime int) (*SQLiteStorage, er
             user_id INTEGER NOT NULL,
             created_at TIMESTAMP NOT NULL
         );
+
+        CREATE TABLE IF NOT EXISTS waitlist (
+            id INTEGER PRIMARY KEY AUTOINCREMENT,
+            user_id INTEGER NOT NULL,
+            chat_id INTEGER NOT NULL,
+            date TEXT NOT NULL,
+            time TEXT NOT NULL DEFAULT '',
+            status TEXT NOT NULL,
+            offered_time TEXT NOT NULL DEFAULT '',
+            offer_expires_at INTEGER NOT NULL DEFAULT 0,
+            created_at TIMESTAMP NOT NULL
+        );
+
+        CREATE TABLE IF NOT EXISTS booking_series (
+            id INTEGER PRIMARY KEY AUTOINCREMENT,
+            user_id INTEGER NOT NULL,
+            frequency TEXT NOT NULL,
+            start_date TEXT NOT NULL,
+            time TEXT NOT NULL,
+            until_date TEXT NOT NULL DEFAULT '',
+            count INTEGER NOT NULL DEFAULT 0,
+            car_model TEXT NOT NULL,
+            car_number TEXT NOT NULL,
+            created_at TIMESTAMP NOT NULL
+        );
+
+        CREATE TABLE IF NOT EXISTS booking_history (
+            id INTEGER PRIMARY KEY AUTOINCREMENT,
+            booking_id TEXT NOT NULL,
+            user_id INTEGER NOT NULL,
+            action TEXT NOT NULL,
+            details TEXT NOT NULL DEFAULT '',
+            actor_id INTEGER NOT NULL,
+            created_at TIMESTAMP NOT NULL
+        );
+
+        CREATE TABLE IF NOT EXISTS settings (
+            key TEXT PRIMARY KEY,
+            value TEXT NOT NULL
+        );
+
+        CREATE TABLE IF NOT EXISTS staff (
+            id INTEGER PRIMARY KEY AUTOINCREMENT,
+            telegram_id INTEGER NOT NULL DEFAULT 0,
+            name TEXT NOT NULL,
+            active INTEGER NOT NULL DEFAULT 1,
+            created_at TIMESTAMP NOT NULL
+        );
+
+        CREATE TABLE IF NOT EXISTS walkins (
+            id INTEGER PRIMARY KEY AUTOINCREMENT,
+            user_id INTEGER NOT NULL,
+            chat_id INTEGER NOT NULL,
+            message_id INTEGER NOT NULL DEFAULT 0,
+            status TEXT NOT NULL,
+            bay INTEGER NOT NULL DEFAULT 0,
+            created_at TIMESTAMP NOT NULL,
+            started_at INTEGER NOT NULL DEFAULT 0
+        );
+
+        CREATE TABLE IF NOT EXISTS shifts (
+            id INTEGER PRIMARY KEY AUTOINCREMENT,
+            staff_id INTEGER NOT NULL,
+            date TEXT NOT NULL,
+            start_time TEXT NOT NULL,
+            end_time TEXT NOT NULL
+        );
+
+        CREATE TABLE IF NOT EXISTS customers (
+            user_id INTEGER PRIMARY KEY,
+            phone TEXT NOT NULL DEFAULT '',
+            language TEXT NOT NULL DEFAULT '',
+            promo_code TEXT NOT NULL DEFAULT '',
+            updated_at TIMESTAMP NOT NULL
+        );
+
+        CREATE TABLE IF NOT EXISTS jobs (
+            id INTEGER PRIMARY KEY AUTOINCREMENT,
+            kind TEXT NOT NULL,
+            booking_id TEXT NOT NULL,
+            slot TEXT NOT NULL,
+            offset_minutes INTEGER NOT NULL,
+            run_at INTEGER NOT NULL,
+            status TEXT NOT NULL,
+            created_at TIMESTAMP NOT NULL,
+            UNIQUE (kind, booking_id, slot, offset_minutes)
+        );
+
+        CREATE TABLE IF NOT EXISTS loyalty_ledger (
+            id INTEGER PRIMARY KEY AUTOINCREMENT,
+            user_id INTEGER NOT NULL,
+            booking_id TEXT NOT NULL DEFAULT '',
+            delta INTEGER NOT NULL,
+            reason TEXT NOT NULL,
+            comment TEXT NOT NULL DEFAULT '',
+            actor_id INTEGER NOT NULL,
+            created_at TIMESTAMP NOT NULL
+        );
+
+        CREATE TABLE IF NOT EXISTS promo_codes (
+            code TEXT PRIMARY KEY,
+            percent INTEGER NOT NULL DEFAULT 0,
+            amount INTEGER NOT NULL DEFAULT 0,
+            valid_from TEXT NOT NULL DEFAULT '',
+            valid_until TEXT NOT NULL DEFAULT '',
+            max_uses INTEGER NOT NULL DEFAULT 0,
+            max_per_user INTEGER NOT NULL DEFAULT 0,
+            services TEXT NOT NULL DEFAULT '',
+            time_from TEXT NOT NULL DEFAULT '',
+            time_to TEXT NOT NULL DEFAULT '',
+            active INTEGER NOT NULL DEFAULT 1,
+            created_at TIMESTAMP NOT NULL
+        );
+
+        CREATE TABLE IF NOT EXISTS payments (
+            id INTEGER PRIMARY KEY AUTOINCREMENT,
+            booking_id TEXT NOT NULL,
+            user_id INTEGER NOT NULL,
+            amount INTEGER NOT NULL,
+            currency TEXT NOT NULL,
+            telegram_charge_id TEXT NOT NULL UNIQUE,
+            provider_charge_id TEXT NOT NULL DEFAULT '',
+            created_at TIMESTAMP NOT NULL
+        );
+
+        CREATE TABLE IF NOT EXISTS feedback (
+            booking_id TEXT PRIMARY KEY,
+            user_id INTEGER NOT NULL,
+            washer_id INTEGER NOT NULL DEFAULT 0,
+            rating INTEGER NOT NULL,
+            comment TEXT NOT NULL DEFAULT '',
+            created_at INTEGER NOT NULL,
+            updated_at TIMESTAMP NOT NULL
+        );
     `); err != nil {
 		return nil, err
 	}
 
+	if err := migrate(db); err != nil {
+		return nil, fmt.Errorf("ошибка миграции базы: %w", err)
+	}
+
 	return &SQLiteStorage{
 		db:        db,
 		StartTime: startTime,
@@ -43,50 +189,80 @@ func NewSQLiteStorage(dbPath string, startTime, endTime int) (*SQLiteStorage, er
 	}, nil
 }
 
-func (s *SQLiteStorage) AddBooking(booking models.Booking) error {
-	_, err := s.db.Exec(`
-		INSERT INTO bookings (id, date, time, car_model, car_number, user_id, created_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?)
-	`, booking.ID, booking.Date, booking.Time, booking.CarModel, booking.CarNumber, booking.UserID, booking.Created)
-	return err
-}
+// migrate добавляет колонки, появившиеся после создания базы
+func migrate(db *sql.DB) error {
+	columns := []struct {
+		table, name, definition string
+	}{
+		{"bookings", "series_id", "INTEGER NOT NULL DEFAULT 0"},
+		{"bookings", "service", "TEXT NOT NULL DEFAULT ''"},
+		{"bookings", "car_class", "TEXT NOT NULL DEFAULT ''"},
+		{"bookings", "price", "INTEGER NOT NULL DEFAULT 0"},
+		{"bookings", "duration", "INTEGER NOT NULL DEFAULT 60"},
+		{"bookings", "buffer", "INTEGER NOT NULL DEFAULT 0"},
+		{"bookings", "bay", "INTEGER NOT NULL DEFAULT 1"},
+		{"bookings", "washer_id", "INTEGER NOT NULL DEFAULT 0"},
+		{"bookings", "status", "TEXT NOT NULL DEFAULT 'active'"},
+		{"bookings", "loyalty_points", "INTEGER NOT NULL DEFAULT 0"},
+		{"customers", "language", "TEXT NOT NULL DEFAULT ''"},
+		{"customers", "promo_code", "TEXT NOT NULL DEFAULT ''"},
+		{"bookings", "promo_code", "TEXT NOT NULL DEFAULT ''"},
+		{"bookings", "discount", "INTEGER NOT NULL DEFAULT 0"},
+	}
 
-func (s *SQLiteStorage) IsTimeAvailable(date, time string) (bool, error) {
-	var count int
-	err := s.db.QueryRow(`
-		SELECT COUNT(*) FROM bookings
-		WHERE date = ? AND time = ?
-	`, date, time).Scan(&count)
-	return count == 0, err
+	for _, c := range columns {
+		exists, err := columnExists(db, c.table, c.name)
+		if err != nil {
+			return err
+		}
+		if exists {
+			continue
+		}
+		if _, err := db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", c.table, c.name, c.definition)); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
-func (s *SQLiteStorage) GetAllBookings() ([]models.Booking, error) {
-	rows, err := s.db.Query(`
-		SELECT id, date, time, car_model, car_number, user_id, created_at
-		FROM bookings
-	`)
+func columnExists(db *sql.DB, table, column string) (bool, error) {
+	rows, err := db.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
 	if err != nil {
-		return nil, err
+		return false, err
 	}
 	defer rows.Close()
 
-	var bookings []models.Booking
 	for rows.Next() {
-		var b models.Booking
-		if err := rows.Scan(&b.ID, &b.Date, &b.Time, &b.CarModel, &b.CarNumber, &b.UserID, &b.Created); err != nil {
-			return nil, err
+		var (
+			cid        int
+			name, kind string
+			notNull    int
+			dflt       sql.NullString
+			pk         int
+		)
+		if err := rows.Scan(&cid, &name, &kind, &notNull, &dflt, &pk); err != nil {
+			return false, err
+		}
+		if name == column {
+			return true, nil
 		}
-		bookings = append(bookings, b)
 	}
-	return bookings, nil
+	return false, rows.Err()
 }
 
-func (s *SQLiteStorage) GetBookingsByDate(date string) ([]models.Booking, error) {
-	rows, err := s.db.Query(`
-		SELECT id, date, time, car_model, car_number, user_id, created_at
-		FROM bookings
-		WHERE date = ?
-	`, date)
+const bookingColumns = `id, date, time, car_model, car_number, user_id, created_at, series_id, service, car_class, price,
+	duration, buffer, bay, washer_id, status, loyalty_points, promo_code, discount`
+
+func scanBooking(row interface{ Scan(...any) error }) (models.Booking, error) {
+	var b models.Booking
+	err := row.Scan(&b.ID, &b.Date, &b.Time, &b.CarModel, &b.CarNumber, &b.UserID, &b.Created, &b.SeriesID,
+		&b.Service, &b.CarClass, &b.Price, &b.Duration, &b.Buffer, &b.Bay, &b.WasherID, &b.Status,
+		&b.LoyaltyPoints, &b.PromoCode, &b.Discount)
+	return b, err
+}
+
+func (s *SQLiteStorage) queryBookings(query string, args ...any) ([]models.Booking, error) {
+	rows, err := s.db.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -94,31 +270,53 @@ func (s *SQLiteStorage) GetBookingsByDate(date string) ([]models.Booking, error)
 
 	var bookings []models.Booking
 	for rows.Next() {
-		var b models.Booking
-		if err := rows.Scan(&b.ID, &b.Date, &b.Time, &b.CarModel, &b.CarNumber, &b.UserID, &b.Created); err != nil {
+		b, err := scanBooking(rows)
+		if err != nil {
 			return nil, err
 		}
 		bookings = append(bookings, b)
 	}
-	return bookings, nil
+	return bookings, rows.Err()
 }
 
-func (s *SQLiteStorage) GetBookingByID(id string) (*models.Booking, error) {
-	var booking models.Booking
+func (s *SQLiteStorage) AddBooking(booking models.Booking) error {
+	_, err := s.db.Exec(`
+		INSERT INTO bookings (id, date, time, car_model, car_number, user_id, created_at, series_id, service, car_class, price,
+			duration, buffer, bay, washer_id, status, loyalty_points, promo_code, discount)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, booking.ID, booking.Date, booking.Time, booking.CarModel, booking.CarNumber, booking.UserID, booking.Created,
+		booking.SeriesID, booking.Service, booking.CarClass, booking.Price, booking.Duration, booking.Buffer, booking.Bay,
+		booking.WasherID, booking.Status, booking.LoyaltyPoints, booking.PromoCode, booking.Discount)
+	return err
+}
 
+func (s *SQLiteStorage) IsTimeAvailable(date, time string) (bool, error) {
+	var count int
 	err := s.db.QueryRow(`
-        SELECT id, date, time, car_model, car_number, user_id, created_at
+		SELECT COUNT(*) FROM bookings
+		WHERE date = ? AND time = ?
+	`, date, time).Scan(&count)
+	return count == 0, err
+}
+
+func (s *SQLiteStorage) GetAllBookings() ([]models.Booking, error) {
+	return s.queryBookings(`SELECT ` + bookingColumns + ` FROM bookings`)
+}
+
+func (s *SQLiteStorage) GetBookingsByDate(date string) ([]models.Booking, error) {
+	return s.queryBookings(`
+		SELECT `+bookingColumns+`
+		FROM bookings
+		WHERE date = ?
+	`, date)
+}
+
+func (s *SQLiteStorage) GetBookingByID(id string) (*models.Booking, error) {
+	booking, err := scanBooking(s.db.QueryRow(`
+        SELECT `+bookingColumns+`
         FROM bookings
         WHERE id = ?
-    `, id).Scan(
-		&booking.ID,
-		&booking.Date,
-		&booking.Time,
-		&booking.CarModel,
-		&booking.CarNumber,
-		&booking.UserID,
-		&booking.Created,
-	)
+    `, id))
 
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -130,51 +328,119 @@ func (s *SQLiteStorage) GetBookingByID(id string) (*models.Booking, error) {
 	return &booking, nil
 }
 
+// MoveBooking переносит запись на новые дату, время, пост и мойщика из booking, сохраняя её ID,
+// и обновляет цену, скидку и время уборки. Проверка поста и обновление идут в одной транзакции.
+// Возвращает ErrSlotTaken, если мойка вместе с уборкой пересекается на этом посту с другой записью
+// (по тем же правилам, что services.FreeBays). Клиентов живой очереди проверяет вызывающий код
+func (s *SQLiteStorage) MoveBooking(booking models.Booking) error {
+	start, err := models.SlotTime(booking.Date, booking.Time)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`
+		SELECT time, duration, buffer FROM bookings
+		WHERE date = ? AND bay = ? AND id != ? AND status != ?
+	`, booking.Date, booking.Bay, booking.ID, models.BookingNoShow)
+	if err != nil {
+		return err
+	}
+	var occupied []services.Occupied
+	for rows.Next() {
+		var timeStr string
+		var duration, buffer int
+		if err := rows.Scan(&timeStr, &duration, &buffer); err != nil {
+			rows.Close()
+			return err
+		}
+		other, err := models.SlotTime(booking.Date, timeStr)
+		if err != nil {
+			continue
+		}
+		if duration <= 0 {
+			duration = services.DefaultServiceDuration
+		}
+		occupied = append(occupied, services.Occupied{
+			Bay:   booking.Bay,
+			Start: other,
+			End:   other.Add(time.Duration(duration+buffer) * time.Minute),
+		})
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	duration := booking.Duration
+	if duration <= 0 {
+		duration = services.DefaultServiceDuration
+	}
+	bays := []services.Bay{{Number: booking.Bay, Buffer: time.Duration(booking.Buffer) * time.Minute}}
+	if len(services.FreeBays(occupied, bays, start, time.Duration(duration)*time.Minute, 0)) == 0 {
+		return ErrSlotTaken
+	}
+
+	res, err := tx.Exec(`
+		UPDATE bookings SET date = ?, time = ?, bay = ?, buffer = ?, price = ?, washer_id = ?, promo_code = ?, discount = ?
+		WHERE id = ?
+	`, booking.Date, booking.Time, booking.Bay, booking.Buffer, booking.Price, booking.WasherID,
+		booking.PromoCode, booking.Discount, booking.ID)
+	if err != nil {
+		return err
+	}
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return sql.ErrNoRows
+	}
+	return tx.Commit()
+}
+
 func (s *SQLiteStorage) DeleteBooking(id string) error {
 	_, err := s.db.Exec("DELETE FROM bookings WHERE id = ?", id)
 	return err
 }
 
+// GetUserBookings возвращает действующие записи пользователя: активные, ждущие подтверждения
+// и неоплаченные, за которыми пока удерживается время
 func (s *SQLiteStorage) GetUserBookings(userID int64) ([]models.Booking, error) {
-	rows, err := s.db.Query(`
-		SELECT id, date, time, car_model, car_number, user_id, created_at
+	return s.queryBookings(`
+		SELECT `+bookingColumns+`
 		FROM bookings
-		WHERE user_id = ?
-	`, userID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
+		WHERE user_id = ? AND status IN (?, ?, ?)
+	`, userID, models.BookingActive, models.BookingPending, models.BookingAwaitingPayment)
+}
 
-	var bookings []models.Booking
-	for rows.Next() {
-		var b models.Booking
-		if err := rows.Scan(&b.ID, &b.Date, &b.Time, &b.CarModel, &b.CarNumber, &b.UserID, &b.Created); err != nil {
-			return nil, err
-		}
-		bookings = append(bookings, b)
-	}
-	return bookings, nil
+func (s *SQLiteStorage) SetBookingStatus(id, status string) error {
+	_, err := s.db.Exec("UPDATE bookings SET status = ? WHERE id = ?", status, id)
+	return err
+}
+
+// CountNoShows возвращает число неявок клиента
+func (s *SQLiteStorage) CountNoShows(userID int64) (int, error) {
+	var count int
+	err := s.db.QueryRow(`
+		SELECT COUNT(*) FROM bookings WHERE user_id = ? AND status = ?
+	`, userID, models.BookingNoShow).Scan(&count)
+	return count, err
 }
 
 func (s *SQLiteStorage) GetBookingsByDateTime(date, time string) ([]models.Booking, error) {
-	rows, err := s.db.Query(`
-		SELECT id, date, time, car_model, car_number, user_id, created_at
+	return s.queryBookings(`
+		SELECT `+bookingColumns+`
 		FROM bookings
 		WHERE date = ? AND time = ?
 	`, date, time)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
+}
 
-	var bookings []models.Booking
-	for rows.Next() {
-		var b models.Booking
-		if err := rows.Scan(&b.ID, &b.Date, &b.Time, &b.CarModel, &b.CarNumber, &b.UserID, &b.Created); err != nil {
-			return nil, err
-		}
-		bookings = append(bookings, b)
-	}
-	return bookings, nil
+func (s *SQLiteStorage) GetBookingsByCarNumber(carNumber string) ([]models.Booking, error) {
+	return s.queryBookings(`
+		SELECT `+bookingColumns+`
+		FROM bookings
+		WHERE car_number = ?
+	`, carNumber)
 }
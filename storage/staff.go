@@ -0,0 +1,129 @@
+package storage
+
+import (
+	"carwash-bot/internal/models"
+	"database/sql"
+)
+
+// AddStaff добавляет мойщика и возвращает его ID
+func (s *SQLiteStorage) AddStaff(staff models.Staff) (int64, error) {
+	res, err := s.db.Exec(`
+		INSERT INTO staff (telegram_id, name, active, created_at)
+		VALUES (?, ?, 1, ?)
+	`, staff.TelegramID, staff.Name, staff.Created)
+	if err != nil {
+		return 0, err
+	}
+	return res.LastInsertId()
+}
+
+func (s *SQLiteStorage) GetStaff(id int64) (*models.Staff, error) {
+	return s.getStaff("SELECT id, telegram_id, name, active, created_at FROM staff WHERE id = ?", id)
+}
+
+// GetStaffByTelegramID возвращает работающего мойщика по его Telegram ID
+func (s *SQLiteStorage) GetStaffByTelegramID(telegramID int64) (*models.Staff, error) {
+	return s.getStaff(`
+		SELECT id, telegram_id, name, active, created_at FROM staff
+		WHERE telegram_id = ? AND active = 1
+	`, telegramID)
+}
+
+func (s *SQLiteStorage) getStaff(query string, args ...any) (*models.Staff, error) {
+	var staff models.Staff
+	err := s.db.QueryRow(query, args...).Scan(&staff.ID, &staff.TelegramID, &staff.Name, &staff.Active, &staff.Created)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &staff, nil
+}
+
+// ListStaff возвращает работающих мойщиков
+func (s *SQLiteStorage) ListStaff() ([]models.Staff, error) {
+	rows, err := s.db.Query(`
+		SELECT id, telegram_id, name, active, created_at FROM staff
+		WHERE active = 1
+		ORDER BY id
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var staff []models.Staff
+	for rows.Next() {
+		var st models.Staff
+		if err := rows.Scan(&st.ID, &st.TelegramID, &st.Name, &st.Active, &st.Created); err != nil {
+			return nil, err
+		}
+		staff = append(staff, st)
+	}
+	return staff, rows.Err()
+}
+
+// DeactivateStaff убирает мойщика из графика. Его смены перестают учитываться,
+// история назначений сохраняется
+func (s *SQLiteStorage) DeactivateStaff(id int64) error {
+	_, err := s.db.Exec("UPDATE staff SET active = 0 WHERE id = ?", id)
+	return err
+}
+
+func (s *SQLiteStorage) AddShift(shift models.Shift) (int64, error) {
+	res, err := s.db.Exec(`
+		INSERT INTO shifts (staff_id, date, start_time, end_time)
+		VALUES (?, ?, ?, ?)
+	`, shift.StaffID, shift.Date, shift.Start, shift.End)
+	if err != nil {
+		return 0, err
+	}
+	return res.LastInsertId()
+}
+
+func (s *SQLiteStorage) DeleteShift(id int64) error {
+	_, err := s.db.Exec("DELETE FROM shifts WHERE id = ?", id)
+	return err
+}
+
+// GetShiftsByDate возвращает смены работающих мойщиков на дату
+func (s *SQLiteStorage) GetShiftsByDate(date string) ([]models.Shift, error) {
+	rows, err := s.db.Query(`
+		SELECT sh.id, sh.staff_id, sh.date, sh.start_time, sh.end_time
+		FROM shifts sh
+		JOIN staff st ON st.id = sh.staff_id
+		WHERE sh.date = ? AND st.active = 1
+		ORDER BY sh.start_time, sh.staff_id
+	`, date)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var shifts []models.Shift
+	for rows.Next() {
+		var shift models.Shift
+		if err := rows.Scan(&shift.ID, &shift.StaffID, &shift.Date, &shift.Start, &shift.End); err != nil {
+			return nil, err
+		}
+		shifts = append(shifts, shift)
+	}
+	return shifts, rows.Err()
+}
+
+// SetBookingWasher назначает мойщика на запись, 0 - снять назначение
+func (s *SQLiteStorage) SetBookingWasher(bookingID string, staffID int64) error {
+	_, err := s.db.Exec("UPDATE bookings SET washer_id = ? WHERE id = ?", staffID, bookingID)
+	return err
+}
+
+// GetWasherBookings возвращает записи мойщика на дату по порядку
+func (s *SQLiteStorage) GetWasherBookings(staffID int64, date string) ([]models.Booking, error) {
+	return s.queryBookings(`
+		SELECT `+bookingColumns+`
+		FROM bookings
+		WHERE washer_id = ? AND date = ?
+		ORDER BY time
+	`, staffID, date)
+}
@@ -0,0 +1,174 @@
+package storage
+
+import (
+	"carwash-bot/internal/models"
+	"database/sql"
+	"time"
+)
+
+const waitlistColumns = `id, user_id, chat_id, date, time, status, offered_time, offer_expires_at, created_at`
+
+func scanWaitlistEntry(row interface{ Scan(...any) error }) (models.WaitlistEntry, error) {
+	var e models.WaitlistEntry
+	var expires int64
+	err := row.Scan(&e.ID, &e.UserID, &e.ChatID, &e.Date, &e.Time, &e.Status, &e.OfferedTime, &expires, &e.Created)
+	if expires > 0 {
+		e.OfferExpiresAt = time.Unix(expires, 0)
+	}
+	return e, err
+}
+
+func (s *SQLiteStorage) queryWaitlist(query string, args ...any) ([]models.WaitlistEntry, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []models.WaitlistEntry
+	for rows.Next() {
+		e, err := scanWaitlistEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+// AddWaitlistEntry ставит пользователя в лист ожидания и возвращает ID записи
+func (s *SQLiteStorage) AddWaitlistEntry(entry models.WaitlistEntry) (int64, error) {
+	res, err := s.db.Exec(`
+		INSERT INTO waitlist (user_id, chat_id, date, time, status, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, entry.UserID, entry.ChatID, entry.Date, entry.Time, models.WaitlistWaiting, entry.Created)
+	if err != nil {
+		return 0, err
+	}
+	return res.LastInsertId()
+}
+
+func (s *SQLiteStorage) GetWaitlistEntry(id int64) (*models.WaitlistEntry, error) {
+	e, err := scanWaitlistEntry(s.db.QueryRow(`SELECT `+waitlistColumns+` FROM waitlist WHERE id = ?`, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// FindWaitlistEntry ищет активную запись пользователя в листе ожидания на то же время
+func (s *SQLiteStorage) FindWaitlistEntry(userID int64, date, time string) (*models.WaitlistEntry, error) {
+	e, err := scanWaitlistEntry(s.db.QueryRow(`
+		SELECT `+waitlistColumns+` FROM waitlist
+		WHERE user_id = ? AND date = ? AND time = ? AND status IN (?, ?, ?)
+	`, userID, date, time, models.WaitlistWaiting, models.WaitlistOffered, models.WaitlistClaimed))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// WaitlistPosition возвращает место записи в очереди на то же время
+func (s *SQLiteStorage) WaitlistPosition(entry models.WaitlistEntry) (int, error) {
+	var position int
+	err := s.db.QueryRow(`
+		SELECT COUNT(*) FROM waitlist
+		WHERE date = ? AND status = ? AND id <= ? AND (time = ? OR time = '' OR ? = '')
+	`, entry.Date, models.WaitlistWaiting, entry.ID, entry.Time, entry.Time).Scan(&position)
+	return position, err
+}
+
+// NextWaitlistEntry возвращает первого ожидающего на указанное время или на любое время в этот день
+func (s *SQLiteStorage) NextWaitlistEntry(date, time string) (*models.WaitlistEntry, error) {
+	e, err := scanWaitlistEntry(s.db.QueryRow(`
+		SELECT `+waitlistColumns+` FROM waitlist
+		WHERE date = ? AND status = ? AND (time = ? OR time = '')
+		ORDER BY id
+		LIMIT 1
+	`, date, models.WaitlistWaiting, time))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// OfferWaitlistSlot предлагает освободившееся время записи до истечения срока
+func (s *SQLiteStorage) OfferWaitlistSlot(id int64, time string, expiresAt time.Time) error {
+	_, err := s.db.Exec(`
+		UPDATE waitlist SET status = ?, offered_time = ?, offer_expires_at = ?
+		WHERE id = ?
+	`, models.WaitlistOffered, time, expiresAt.Unix(), id)
+	return err
+}
+
+func (s *SQLiteStorage) SetWaitlistStatus(id int64, status string) error {
+	_, err := s.db.Exec("UPDATE waitlist SET status = ? WHERE id = ?", status, id)
+	return err
+}
+
+// GetActiveHolds возвращает предложения листа ожидания, удерживающие время на дату
+func (s *SQLiteStorage) GetActiveHolds(date string, now time.Time) ([]models.WaitlistEntry, error) {
+	return s.queryWaitlist(`
+		SELECT `+waitlistColumns+` FROM waitlist
+		WHERE date = ? AND status IN (?, ?) AND offer_expires_at > ?
+	`, date, models.WaitlistOffered, models.WaitlistClaimed, now.Unix())
+}
+
+// SlotHolder возвращает пользователя, за которым сейчас удерживается время, или 0
+func (s *SQLiteStorage) SlotHolder(date, time string, now time.Time) (int64, error) {
+	var userID int64
+	err := s.db.QueryRow(`
+		SELECT user_id FROM waitlist
+		WHERE date = ? AND offered_time = ? AND status IN (?, ?) AND offer_expires_at > ?
+		LIMIT 1
+	`, date, time, models.WaitlistOffered, models.WaitlistClaimed, now.Unix()).Scan(&userID)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	return userID, err
+}
+
+// ExtendWaitlistOffer отмечает, что пользователь забрал время, и продлевает удержание
+func (s *SQLiteStorage) ExtendWaitlistOffer(id int64, expiresAt time.Time) error {
+	_, err := s.db.Exec(`
+		UPDATE waitlist SET status = ?, offer_expires_at = ?
+		WHERE id = ?
+	`, models.WaitlistClaimed, expiresAt.Unix(), id)
+	return err
+}
+
+// ExpireWaitlistOffers помечает просроченные предложения и возвращает их
+func (s *SQLiteStorage) ExpireWaitlistOffers(now time.Time) ([]models.WaitlistEntry, error) {
+	entries, err := s.queryWaitlist(`
+		SELECT `+waitlistColumns+` FROM waitlist
+		WHERE status IN (?, ?) AND offer_expires_at <= ?
+	`, models.WaitlistOffered, models.WaitlistClaimed, now.Unix())
+	if err != nil {
+		return nil, err
+	}
+
+	for _, e := range entries {
+		if err := s.SetWaitlistStatus(e.ID, models.WaitlistExpired); err != nil {
+			return nil, err
+		}
+	}
+	return entries, nil
+}
+
+// CompleteWaitlistEntries закрывает записи пользователя в листе ожидания после записи на мойку
+func (s *SQLiteStorage) CompleteWaitlistEntries(userID int64, date string) error {
+	_, err := s.db.Exec(`
+		UPDATE waitlist SET status = ?
+		WHERE user_id = ? AND date = ? AND status IN (?, ?, ?)
+	`, models.WaitlistDone, userID, date, models.WaitlistWaiting, models.WaitlistOffered, models.WaitlistClaimed)
+	return err
+}
@@ -0,0 +1,100 @@
+package storage
+
+import (
+	"carwash-bot/internal/models"
+	"database/sql"
+	"time"
+)
+
+const walkInColumns = `id, user_id, chat_id, message_id, status, bay, created_at, started_at`
+
+func scanWalkIn(row interface{ Scan(...any) error }) (models.WalkIn, error) {
+	var w models.WalkIn
+	var startedAt int64
+	err := row.Scan(&w.ID, &w.UserID, &w.ChatID, &w.MessageID, &w.Status, &w.Bay, &w.Created, &startedAt)
+	if startedAt > 0 {
+		w.StartedAt = time.Unix(startedAt, 0)
+	}
+	return w, err
+}
+
+func (s *SQLiteStorage) queryWalkIns(query string, args ...any) ([]models.WalkIn, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var walkIns []models.WalkIn
+	for rows.Next() {
+		w, err := scanWalkIn(rows)
+		if err != nil {
+			return nil, err
+		}
+		walkIns = append(walkIns, w)
+	}
+	return walkIns, rows.Err()
+}
+
+func (s *SQLiteStorage) getWalkIn(query string, args ...any) (*models.WalkIn, error) {
+	w, err := scanWalkIn(s.db.QueryRow(query, args...))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+// AddWalkIn ставит клиента в конец живой очереди
+func (s *SQLiteStorage) AddWalkIn(walkIn models.WalkIn) (int64, error) {
+	res, err := s.db.Exec(`
+		INSERT INTO walkins (user_id, chat_id, status, created_at)
+		VALUES (?, ?, ?, ?)
+	`, walkIn.UserID, walkIn.ChatID, models.WalkInWaiting, walkIn.Created)
+	if err != nil {
+		return 0, err
+	}
+	return res.LastInsertId()
+}
+
+func (s *SQLiteStorage) GetWalkIn(id int64) (*models.WalkIn, error) {
+	return s.getWalkIn("SELECT "+walkInColumns+" FROM walkins WHERE id = ?", id)
+}
+
+// FindActiveWalkIn возвращает место пользователя в очереди (ожидание или обслуживание)
+func (s *SQLiteStorage) FindActiveWalkIn(userID int64) (*models.WalkIn, error) {
+	return s.getWalkIn(`
+		SELECT `+walkInColumns+` FROM walkins
+		WHERE user_id = ? AND status IN (?, ?)
+		ORDER BY id DESC LIMIT 1
+	`, userID, models.WalkInWaiting, models.WalkInServing)
+}
+
+// GetWalkInsByStatus возвращает клиентов очереди в порядке постановки
+func (s *SQLiteStorage) GetWalkInsByStatus(status string) ([]models.WalkIn, error) {
+	return s.queryWalkIns(`
+		SELECT `+walkInColumns+` FROM walkins
+		WHERE status = ?
+		ORDER BY id
+	`, status)
+}
+
+func (s *SQLiteStorage) SetWalkInMessage(id int64, messageID int) error {
+	_, err := s.db.Exec("UPDATE walkins SET message_id = ? WHERE id = ?", messageID, id)
+	return err
+}
+
+// StartWalkIn вызывает клиента на пост
+func (s *SQLiteStorage) StartWalkIn(id int64, bay int, startedAt time.Time) error {
+	_, err := s.db.Exec(`
+		UPDATE walkins SET status = ?, bay = ?, started_at = ? WHERE id = ?
+	`, models.WalkInServing, bay, startedAt.Unix(), id)
+	return err
+}
+
+func (s *SQLiteStorage) SetWalkInStatus(id int64, status string) error {
+	_, err := s.db.Exec("UPDATE walkins SET status = ? WHERE id = ?", status, id)
+	return err
+}